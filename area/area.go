@@ -1,9 +1,7 @@
 package area
 
 import (
-	"fmt"
-	"sort"
-	"strings"
+	"reflect"
 
 	"github.com/erinpentecost/hex"
 )
@@ -17,42 +15,257 @@ var (
 var exists = struct{}{}
 
 // Area is a collection of hexes.
+//
+// Large areas that fill most of their bounding box are kept
+// as bitsets, and everything else is kept in a map.
+// This is picked automatically.
 type Area struct {
-	hexes map[hex.Hex]struct{}
+	// hexes is nil for the zero Area.
+	hexes store
 	// boundsClean is true if the bounding box is ok.
 	// this must be false for empty areas.
 	boundsClean bool
 	// bounding box for the area
 	minR, maxR, minQ, maxQ int64
+	// topology is the world the area was wrapped to,
+	// or nil for the plane.
+	topology hex.Topology
 }
 
 // NewArea creates a new area containing one or more hexes.
 func NewArea(hexes ...hex.Hex) *Area {
-	c := make(map[hex.Hex]struct{})
+	c := make(mapStore, len(hexes))
 	for _, k := range hexes {
 		c[k] = exists
 	}
 	return (&Area{
 		hexes: c,
-	}).ensureBounds()
+	}).pack()
 }
 
-// Slice converts the area into a slice of hexes.
+// Add puts hexes into the area.
 //
-// You can use this to marshal an area.
-func (a *Area) Slice() []hex.Hex {
-	hexes := make([]hex.Hex, len(a.hexes))
-	i := 0
-	for k := range a.hexes {
-		hexes[i] = k
-		i++
+// The bounding box grows to fit the new hexes,
+// so this is cheap to call one hex at a time.
+func (a *Area) Add(hexes ...hex.Hex) *Area {
+	if a.hexes == nil {
+		a.hexes = make(mapStore, len(hexes))
+	}
+	for _, k := range hexes {
+		k = a.canonical(k)
+		a.growBounds(k)
+		a.makeRoom(k)
+		a.hexes.add(k)
+	}
+	return a
+}
+
+// growBounds makes the bounding box fit k,
+// which is about to be added.
+func (a *Area) growBounds(k hex.Hex) {
+	switch {
+	case a.stored().size() == 0:
+		a.minR, a.maxR, a.minQ, a.maxQ = k.R, k.R, k.Q, k.Q
+		a.boundsClean = true
+	case a.boundsClean:
+		a.minR = minInt(a.minR, k.R)
+		a.maxR = maxInt(a.maxR, k.R)
+		a.minQ = minInt(a.minQ, k.Q)
+		a.maxQ = maxInt(a.maxQ, k.Q)
+	}
+}
+
+// shrinkBounds marks the bounding box as dirty if k,
+// which was just removed, was on its edge.
+func (a *Area) shrinkBounds(k hex.Hex) {
+	if k.R == a.minR || k.R == a.maxR || k.Q == a.minQ || k.Q == a.maxQ {
+		a.boundsClean = false
+	}
+}
+
+// Remove takes hexes out of the area.
+//
+// If a removed hex was on the edge of the bounding box,
+// the bounding box is found again the next time it's needed.
+func (a *Area) Remove(hexes ...hex.Hex) *Area {
+	for _, k := range hexes {
+		k = a.canonical(k)
+		if !a.stored().has(k) {
+			continue
+		}
+		a.hexes.remove(k)
+		a.shrinkBounds(k)
+	}
+	if a.Size() == 0 {
+		a.ensureBounds()
+	}
+	return a
+}
+
+// Clear removes every hex from the area.
+func (a *Area) Clear() *Area {
+	a.hexes = make(mapStore)
+	return a.ensureBounds()
+}
+
+// Clone returns a copy of the area that can be changed
+// without changing the original.
+func (a *Area) Clone() *Area {
+	return &Area{
+		hexes:       a.stored().clone(),
+		boundsClean: a.boundsClean,
+		minR:        a.minR,
+		maxR:        a.maxR,
+		minQ:        a.minQ,
+		maxQ:        a.maxQ,
+		topology:    a.topology,
 	}
+}
+
+// Slice converts the area into a slice of hexes.
+// The order of elements returned is not set.
+func (a *Area) Slice() []hex.Hex {
+	hexes := make([]hex.Hex, 0, a.Size())
+	a.stored().each(func(k hex.Hex) bool {
+		hexes = append(hexes, k)
+		return true
+	})
 	return hexes
 }
 
+// Each calls fn with every hex in the area. Areas other than
+// the keys of a HexMap are visited without copying them.
+// It stops early if fn returns false.
+// The order hexes are visited in is not set.
+//
+// fn must not modify the area.
+func (a *Area) Each(fn func(hex.Hex) bool) {
+	// call the concrete stores directly so fn doesn't escape.
+	switch s := a.hexes.(type) {
+	case mapStore:
+		s.each(fn)
+	case *bitStore:
+		s.each(fn)
+	case *runStore:
+		s.each(fn)
+	default:
+		// calling fn through the store interface would make it
+		// escape, so copy the hexes of any other store first.
+		for _, k := range a.Slice() {
+			if !fn(k) {
+				return
+			}
+		}
+	}
+}
+
+// NewAreaFromOffset creates a new area containing the hexes at the
+// given offset coordinates.
+func NewAreaFromOffset(parity hex.OffsetParity, coords ...hex.OffsetCoord) *Area {
+	c := make(mapStore, len(coords))
+	for _, o := range coords {
+		c[o.ToHex(parity)] = exists
+	}
+	return (&Area{
+		hexes: c,
+	}).pack()
+}
+
+// OffsetCoords converts the area into a slice of offset coordinates
+// with the given parity.
+func (a *Area) OffsetCoords(parity hex.OffsetParity) []hex.OffsetCoord {
+	coords := make([]hex.OffsetCoord, 0, a.Size())
+	a.stored().each(func(k hex.Hex) bool {
+		coords = append(coords, k.ToOffset(parity))
+		return true
+	})
+	return coords
+}
+
+// Wrap returns a copy of the area in the world described by t.
+//
+// Every hex is moved to its canonical hex, so parts of the area
+// that stick out past a seam come back in on the other side.
+// Build shapes on the plane as usual, then Wrap them.
+//
+// The wrapped area keeps t, and works in that world from then on.
+// Add, Remove and ContainsHexes wrap the hexes they are given, and
+// BoundaryEdges and InteriorVertices step across seams, so a seam
+// is never a boundary. Union, Intersection, Subtract, Xor and
+// CheckBounding wrap the other area to t before combining them,
+// so shapes line up across seams:
+//
+//	east := area.BigHex(h, 3).Wrap(world)
+//	shared := east.Intersection(area.BigHex(g, 3)).Build()
+//
+// Transforms wrap their result again. Translations always match up
+// across seams, and so do rotations on a HexTorus, but other
+// transforms may not.
+//
+// Encoded areas don't keep their topology,
+// so decoded areas are on the plane.
+func (a *Area) Wrap(t hex.Topology) *Area {
+	c := make(mapStore, a.Size())
+	a.stored().each(func(k hex.Hex) bool {
+		c[t.Canonical(k)] = exists
+		return true
+	})
+	return (&Area{
+		hexes:    c,
+		topology: t,
+	}).pack()
+}
+
+// Topology returns the world the area was wrapped to,
+// or hex.Planar if it was never wrapped.
+func (a *Area) Topology() hex.Topology {
+	if a.topology == nil {
+		return hex.Planar{}
+	}
+	return a.topology
+}
+
+// canonical wraps k to the area's topology.
+func (a *Area) canonical(k hex.Hex) hex.Hex {
+	if a.topology == nil {
+		return k
+	}
+	return a.topology.Canonical(k)
+}
+
+// sameTopology returns true if x and y are known to be
+// the same world.
+func sameTopology(x, y hex.Topology) bool {
+	if x == nil || y == nil {
+		return x == y
+	}
+	// comparing topologies that can't be compared panics.
+	return reflect.TypeOf(x).Comparable() && x == y
+}
+
+// onSameTopology returns a and b wrapped to the same world,
+// and that world. a's topology wins if both are wrapped.
+// The topology is nil if neither area is wrapped.
+func onSameTopology(a, b *Area) (*Area, *Area, hex.Topology) {
+	t := a.topology
+	if t == nil {
+		t = b.topology
+	}
+	if t == nil {
+		return a, b, nil
+	}
+	if !sameTopology(a.topology, t) {
+		a = a.Wrap(t)
+	}
+	if !sameTopology(b.topology, t) {
+		b = b.Wrap(t)
+	}
+	return a, b, t
+}
+
 // Size returns the number of hexes in the area.
 func (a *Area) Size() int {
-	return len(a.hexes)
+	return a.stored().size()
 }
 
 // Equals returns true if both areas share exactly the same hexes.
@@ -68,8 +281,9 @@ func (a *Area) Equals(b *Area) bool {
 // If you want to determine the overlap relationship between two areas,
 // use CheckBounding(), which is more optimized for that task.
 func (a *Area) ContainsHexes(hexes ...hex.Hex) bool {
+	s := a.stored()
 	for _, k := range hexes {
-		if _, ok := a.hexes[k]; !ok {
+		if !s.has(a.canonical(k)) {
 			return false
 		}
 	}
@@ -81,21 +295,55 @@ func (a *Area) Center() hex.HexFractional {
 	return hex.Center(a.Slice()...)
 }
 
+// BoundaryEdges returns the edges between hexes in the area
+// and hexes outside of it.
+// The order of elements returned is not set.
+func (a *Area) BoundaryEdges() []hex.HexEdge {
+	edges := make([]hex.HexEdge, 0)
+	s := a.stored()
+	s.each(func(k hex.Hex) bool {
+		for i := 0; i < 6; i++ {
+			if !s.has(a.canonical(k.Neighbor(i))) {
+				edges = append(edges, k.Edge(i))
+			}
+		}
+		return true
+	})
+	return edges
+}
+
+// InteriorVertices returns the vertices whose three hexes
+// are all in the area.
+// The order of elements returned is not set.
+func (a *Area) InteriorVertices() []hex.HexVertex {
+	vertices := make([]hex.HexVertex, 0)
+	a.stored().each(func(k hex.Hex) bool {
+		// each vertex is owned by exactly one hex as corner 0 or 1,
+		// so only look at those to avoid duplicates.
+		for i := 0; i < 2; i++ {
+			v := k.Vertex(i)
+			hexes := v.Hexes()
+			if a.ContainsHexes(hexes[:]...) {
+				vertices = append(vertices, v)
+			}
+		}
+		return true
+	})
+	return vertices
+}
+
+// String converts the area into a JSON array of hexes.
 func (a *Area) String() string {
-	s := []string{}
-	for k := range a.hexes {
-		ks := fmt.Sprintf("{\"Q\":%d,\"R\":%d}", k.Q, k.R)
-		i := sort.SearchStrings(s, ks)
-		s = append(s, "")
-		copy(s[i+1:], s[i:])
-		s[i] = ks
+	b, err := a.MarshalJSON()
+	if err != nil {
+		return err.Error()
 	}
-	return "[" + strings.Join(s, ",") + "]"
+	return string(b)
 }
 
 // ensureBounds updates the bounding box if necessary.
 func (a *Area) ensureBounds() *Area {
-	if len(a.hexes) == 0 {
+	if a.Size() == 0 {
 		a.boundsClean = false
 		a.minR = 0
 		a.maxR = 0
@@ -106,58 +354,96 @@ func (a *Area) ensureBounds() *Area {
 		return a
 	}
 
-	bf := boundsFinder{}
+	if b, ok := a.hexes.(bounder); ok {
+		a.minR, a.maxR, a.minQ, a.maxQ, a.boundsClean = b.bounds()
+		return a
+	}
 
-	for p := range a.hexes {
+	bf := boundsFinder{}
+	a.hexes.each(func(p hex.Hex) bool {
 		bf.visit(&p)
-	}
+		return true
+	})
 
 	return bf.applyTo(a)
 }
 
+// stored returns the hexes in the area.
+// It never returns nil, so it's safe for zero Areas.
+func (a *Area) stored() store {
+	if a.hexes == nil {
+		return mapStore(nil)
+	}
+	return a.hexes
+}
+
+// Build returns the area itself, not a copy.
+// Use Clone before changing it if it's shared.
 func (a *Area) Build() *Area {
 	return a.ensureBounds()
 }
 
 func (a *Area) Union(b Builder) Builder {
 	return (&areaBuilder{
-		left: a,
-		opt:  noop,
+		left:   a,
+		opt:    noop,
+		height: 1,
 	}).Union(b)
 }
 
 func (a *Area) Intersection(b Builder) Builder {
 	return (&areaBuilder{
-		left: a,
-		opt:  noop,
+		left:   a,
+		opt:    noop,
+		height: 1,
 	}).Intersection(b)
 }
 
 func (a *Area) Subtract(b Builder) Builder {
 	return (&areaBuilder{
-		left: a,
-		opt:  noop,
+		left:   a,
+		opt:    noop,
+		height: 1,
 	}).Subtract(b)
 }
 
+func (a *Area) Xor(b Builder) Builder {
+	return (&areaBuilder{
+		left:   a,
+		opt:    noop,
+		height: 1,
+	}).Xor(b)
+}
+
 func (a *Area) Rotate(pivot hex.Hex, direction int) Builder {
 	return (&areaBuilder{
-		left: a,
-		opt:  noop,
+		left:   a,
+		opt:    noop,
+		height: 1,
 	}).Rotate(pivot, direction)
 }
 
+func (a *Area) Reflect(pivot hex.Hex, axis hex.Axis) Builder {
+	return (&areaBuilder{
+		left:   a,
+		opt:    noop,
+		height: 1,
+	}).Reflect(pivot, axis)
+}
+
 func (a *Area) Translate(offset hex.Hex) Builder {
 	return (&areaBuilder{
-		left: a,
-		opt:  noop,
+		left:   a,
+		opt:    noop,
+		height: 1,
 	}).Translate(offset)
 }
 
-func (a *Area) Transform(t [4][4]int64) Builder {
+func (a *Area) Transform(t hex.Transform) Builder {
 	return (&areaBuilder{
-		left: a,
-		opt:  noop,
+		left:   a,
+		opt:    noop,
+		height: 1,
 	}).Transform(t)
 }
 
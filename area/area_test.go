@@ -0,0 +1,111 @@
+package area
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/erinpentecost/hex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// requireSameBounds checks a's bounds against an area built from scratch.
+func requireSameBounds(t *testing.T, a *Area) {
+	fresh := NewArea(a.Slice()...)
+	minR, maxR, minQ, maxQ, err := a.Bounds()
+	fMinR, fMaxR, fMinQ, fMaxQ, fErr := fresh.Bounds()
+	require.Equal(t, fErr, err)
+	require.Equal(t, []int64{fMinR, fMaxR, fMinQ, fMaxQ}, []int64{minR, maxR, minQ, maxQ})
+}
+
+func TestBoundsDontIncludeOrigin(t *testing.T) {
+	minR, maxR, minQ, maxQ, err := NewArea(hex.Hex{Q: 5, R: 7}, hex.Hex{Q: 6, R: 9}).Bounds()
+	require.NoError(t, err)
+	assert.Equal(t, []int64{7, 9, 5, 6}, []int64{minR, maxR, minQ, maxQ})
+}
+
+func TestAddAndRemove(t *testing.T) {
+	rng := rand.New(rand.NewSource(21))
+	a := NewArea()
+	expected := make(map[hex.Hex]bool)
+	for i := 0; i < 2000; i++ {
+		h := hex.Hex{Q: rng.Int63n(21) - 10, R: rng.Int63n(21) - 10}
+		if rng.Intn(3) == 0 {
+			a.Remove(h)
+			delete(expected, h)
+		} else {
+			a.Add(h)
+			expected[h] = true
+		}
+		require.Equal(t, len(expected), a.Size())
+		if i%50 == 0 {
+			requireSameBounds(t, a)
+		}
+	}
+	for h := range expected {
+		require.True(t, a.ContainsHexes(h))
+	}
+	requireSameBounds(t, a)
+
+	// removing everything leaves an empty area.
+	a.Remove(a.Slice()...)
+	assert.Equal(t, 0, a.Size())
+	_, _, _, _, err := a.Bounds()
+	assert.ErrorIs(t, err, ErrEmptyArea)
+}
+
+func TestAddKeepsBoundsClean(t *testing.T) {
+	a := NewArea()
+	a.Add(hex.Hex{Q: 3, R: -2})
+	assert.True(t, a.boundsClean)
+	a.Add(hex.Hex{Q: -1, R: 4}, hex.Hex{Q: 0, R: 0})
+	assert.True(t, a.boundsClean)
+	requireSameBounds(t, a)
+
+	// removing from the middle doesn't dirty the bounds.
+	a.Remove(hex.Hex{Q: 0, R: 0})
+	assert.True(t, a.boundsClean)
+	a.Remove(hex.Hex{Q: 3, R: -2})
+	assert.False(t, a.boundsClean)
+	requireSameBounds(t, a)
+
+	// the zero value works too.
+	var z Area
+	z.Add(hex.Hex{Q: 1, R: 1})
+	assert.Equal(t, 1, z.Size())
+	requireSameBounds(t, &z)
+}
+
+func TestClearAndClone(t *testing.T) {
+	a := BigHex(hex.Hex{Q: 2, R: -2}, 3)
+	c := a.Clone()
+	assert.True(t, a.Equals(c))
+
+	c.Remove(hex.Hex{Q: 2, R: -2})
+	c.Add(hex.Hex{Q: 40, R: 40})
+	assert.True(t, a.ContainsHexes(hex.Hex{Q: 2, R: -2}))
+	assert.False(t, a.ContainsHexes(hex.Hex{Q: 40, R: 40}))
+	requireSameBounds(t, a)
+	requireSameBounds(t, c)
+
+	a.Clear()
+	assert.Equal(t, 0, a.Size())
+	assert.Equal(t, 37, c.Size())
+	a.Add(hex.Hex{Q: -7, R: 1})
+	requireSameBounds(t, a)
+}
+
+func TestBuildResultsAreNotShared(t *testing.T) {
+	a := BigHex(hex.Origin(), 2)
+	far := BigHex(hex.Hex{Q: 100, R: 100}, 2)
+
+	// subtracting something far away used to return a itself.
+	b := a.Subtract(far).Build()
+	b.Add(hex.Hex{Q: 50, R: 50})
+	assert.False(t, a.ContainsHexes(hex.Hex{Q: 50, R: 50}))
+
+	empty := NewArea()
+	rotated := empty.Rotate(hex.Origin(), 1).Build()
+	rotated.Add(hex.Origin())
+	assert.Equal(t, 0, empty.Size())
+}
@@ -4,7 +4,6 @@ import (
 	"sync"
 
 	"github.com/erinpentecost/hex"
-	"github.com/erinpentecost/hex/internal"
 )
 
 var (
@@ -17,6 +16,7 @@ const (
 	union operation = iota
 	intersection
 	subtract
+	xor
 	transform
 	noop
 )
@@ -29,6 +29,8 @@ func (o operation) String() string {
 		return "i"
 	case subtract:
 		return "s"
+	case xor:
+		return "x"
 	case transform:
 		return "t"
 	case noop:
@@ -44,13 +46,25 @@ func (o operation) String() string {
 type areaBuilder struct {
 	left  Builder
 	right Builder
-	t     [4][4]int64
+	t     hex.Transform
 	opt   operation
+	// height is the length of the longest path to a leaf.
+	height int64
+}
+
+// newNode makes a node that combines left and right with opt.
+func newNode(left, right Builder, opt operation) *areaBuilder {
+	return &areaBuilder{
+		left:   left,
+		right:  right,
+		opt:    opt,
+		height: maxInt(height(left), height(right)) + 1,
+	}
 }
 
 func height(b Builder) int64 {
 	if bb, ok := b.(*areaBuilder); ok {
-		return maxInt(height(bb.left), height(bb.right)) + 1
+		return bb.height
 	}
 	return 1
 }
@@ -65,20 +79,14 @@ func (ab *areaBuilder) Union(b Builder) Builder {
 	// We can optimize this since unions are associative operations.
 
 	// Normally, we make a new node and stick ab as a child and b as another.
-	// But if those children have different heights AND
-	// both those children are also unions, THEN
-	// we can do a rotation insertion.
+	// But if ab is also a union AND its left child is taller than its right,
+	// THEN we can do a rotation insertion and add b to the right child instead.
 
 	// optimization: rotate subtree
-	// bad (big b): union(a, union(b, union(c, d)))
 	// bad (big a): union(union(union(a, b), c), d)
 	// good: union(union(a, b), union(c,d))
 
-	return &areaBuilder{
-		left:  ab,
-		right: b,
-		opt:   union,
-	}
+	return ab.associative(b, union)
 }
 
 func (ab *areaBuilder) Intersection(b Builder) Builder {
@@ -86,47 +94,78 @@ func (ab *areaBuilder) Intersection(b Builder) Builder {
 	// We can optimize interstions in the same way as unions since insertions
 	// are also associative.
 
-	return &areaBuilder{
-		left:  ab,
-		right: b,
-		opt:   intersection,
-	}
+	return ab.associative(b, intersection)
 }
 
 func (ab *areaBuilder) Subtract(b Builder) Builder {
 
 	// Subtractions can't be optimized.
 
-	return &areaBuilder{
-		left:  ab,
-		right: b,
-		opt:   subtract,
+	return newNode(ab, b, subtract)
+}
+
+func (ab *areaBuilder) Xor(b Builder) Builder {
+
+	// Symmetric differences are associative too.
+
+	return ab.associative(b, xor)
+}
+
+// associative adds b to the tree with an associative operation,
+// rotating the tree to keep it balanced.
+func (ab *areaBuilder) associative(b Builder, opt operation) Builder {
+	if ab.opt == opt && height(ab.left) > height(ab.right) {
+		// opt(opt(l, r), b) == opt(l, opt(r, b))
+		return newNode(ab.left, combine(ab.right, b, opt), opt)
 	}
+	return newNode(ab, b, opt)
+}
+
+// combine joins a and b with opt, using their own methods
+// so that they get a chance to balance the tree.
+func combine(a, b Builder, opt operation) Builder {
+	switch opt {
+	case union:
+		return a.Union(b)
+	case intersection:
+		return a.Intersection(b)
+	case xor:
+		return a.Xor(b)
+	}
+	panic("unsupported operation")
 }
 
 func (ab *areaBuilder) Rotate(pivot hex.Hex, direction int) Builder {
-	return ab.
-		Transform(internal.TranslateMatrix(-1*pivot.Q, -1*pivot.R, -1*pivot.S())).
-		Transform(internal.RotateMatrix(direction)).
-		Transform(internal.TranslateMatrix(pivot.Q, pivot.R, pivot.S()))
+	return ab.Transform(hex.RotateTransform(pivot, direction))
+}
+
+func (ab *areaBuilder) Reflect(pivot hex.Hex, axis hex.Axis) Builder {
+	return ab.Transform(hex.ReflectTransform(pivot, axis))
 }
 
 func (ab *areaBuilder) Translate(offset hex.Hex) Builder {
-	return ab.Transform(internal.TranslateMatrix(offset.Q, offset.R, offset.S()))
+	return ab.Transform(hex.TranslateTransform(offset))
 }
 
 // Transform applies a transformation matrix to all hexes in ab.
-func (ab *areaBuilder) Transform(t [4][4]int64) Builder {
+func (ab *areaBuilder) Transform(t hex.Transform) Builder {
 	// if we are chaining transforms, combine them.
+	// make a new node rather than changing ab,
+	// since other builders may share it.
 	if ab.opt == transform {
 		// ab.t is applied first, then t.
-		ab.t = internal.MatrixMultiply(t, ab.t)
-		return ab
+		return &areaBuilder{
+			left:   ab.left,
+			t:      ab.t.Compose(t),
+			opt:    transform,
+			height: ab.height,
+		}
 	}
 	return &areaBuilder{
-		left: ab,
-		t:    t,
-		opt:  transform,
+		left:   ab,
+		t:      t,
+		opt:    transform,
+		height: height(ab) + 1,
 	}
 }
 
@@ -137,21 +176,11 @@ func (ab *areaBuilder) Build() *Area {
 
 	if ab.opt == transform {
 		a := ab.left.Build()
-
-		if len(a.hexes) == 0 {
-			return a
+		c := transformFn(a, ab.t)
+		if a.topology != nil {
+			return c.Wrap(a.topology)
 		}
-
-		// apply transform to all hexes
-		bf := boundsFinder{}
-		out := NewArea()
-		for k := range a.hexes {
-			h := k.Transform(ab.t)
-			out.hexes[h] = exists
-			bf.visit(&h)
-		}
-
-		return bf.applyTo(out)
+		return c
 	}
 
 	// Build() allows me to defer iteration until it's needed,
@@ -169,78 +198,225 @@ func (ab *areaBuilder) Build() *Area {
 
 	wg.Wait()
 
+	// combine wrapped areas in their own world.
+	a, c, t := onSameTopology(a, c)
+	var out *Area
 	switch ab.opt {
 	case union:
-		return unionFn(a, c)
+		out = unionFn(a, c)
 	case intersection:
-		return intersectionFn(a, c)
+		out = intersectionFn(a, c)
 	case subtract:
-		return subtractFn(a, c)
+		out = subtractFn(a, c)
+	case xor:
+		out = xorFn(a, c)
+	default:
+		panic("unsupported operation")
 	}
-	panic("unsupported operation")
+	out.topology = t
+	return out
 }
 
-func unionFn(a *Area, b *Area) *Area {
-	c := make(map[hex.Hex]struct{})
-	for k := range a.hexes {
-		c[k] = exists
+// transformFn returns a copy of a with t applied to every hex.
+func transformFn(a *Area, t hex.Transform) *Area {
+	if a.Size() == 0 {
+		return NewArea()
 	}
-	for k := range b.hexes {
-		c[k] = exists
+
+	// runs stay runs when they are only moved.
+	if runs, ok := a.hexes.(*runStore); ok {
+		offset := hex.Origin().Transform(t)
+		if t == hex.TranslateTransform(offset) {
+			return (&Area{hexes: runs.translate(offset)}).pack()
+		}
 	}
+
+	// the transform is affine, so the corners of
+	// the old bounding box bound the new one.
+	corners := boundsFinder{}
+	for _, k := range []hex.Hex{
+		{Q: a.minQ, R: a.minR},
+		{Q: a.maxQ, R: a.minR},
+		{Q: a.minQ, R: a.maxR},
+		{Q: a.maxQ, R: a.maxR},
+	} {
+		h := k.Transform(t)
+		corners.visit(&h)
+	}
+
+	// apply transform to all hexes
+	bf := boundsFinder{}
+	out := &Area{
+		hexes: newStore(a.Size(), corners.minR, corners.maxR, corners.minQ, corners.maxQ),
+	}
+	a.hexes.each(func(k hex.Hex) bool {
+		h := k.Transform(t)
+		out.hexes.add(h)
+		bf.visit(&h)
+		return true
+	})
+
+	return bf.applyTo(out).pack()
+}
+
+// unionFn returns all the hexes in either area.
+// this operation is commutative.
+func unionFn(a *Area, b *Area) *Area {
+	if a.Size() == 0 {
+		return b.Clone()
+	} else if b.Size() == 0 {
+		return a.Clone()
+	}
+	a.ensureBounds()
+	b.ensureBounds()
+
 	// we can determine a new bounding box
 	// without iterating on the points if we
 	// do it now
-	if a.boundsClean && b.boundsClean {
-		return &Area{
-			hexes:       c,
-			boundsClean: true,
-			minR:        minInt(a.minR, b.minR),
-			maxR:        maxInt(a.maxR, b.maxR),
-			minQ:        minInt(a.minQ, b.minQ),
-			maxQ:        maxInt(a.maxQ, b.maxQ),
+	c := &Area{
+		boundsClean: true,
+		minR:        minInt(a.minR, b.minR),
+		maxR:        maxInt(a.maxR, b.maxR),
+		minQ:        minInt(a.minQ, b.minQ),
+		maxQ:        maxInt(a.maxQ, b.maxQ),
+	}
+
+	dense := isDense(a.Size()+b.Size(), c.minR, c.maxR, c.minQ, c.maxQ)
+	if ba, ok := a.hexes.(*bitStore); ok && dense {
+		if bb, ok := b.hexes.(*bitStore); ok {
+			c.hexes = unionBits(ba, bb)
+			return c.pack()
 		}
 	}
-	return &Area{
-		hexes: c,
+	if ra, ok := a.hexes.(*runStore); ok {
+		if rb, ok := b.hexes.(*runStore); ok {
+			c.hexes = mergeRows(ra, rb, unionRuns)
+			return c.pack()
+		}
 	}
+
+	c.hexes = newStore(a.Size()+b.Size(), c.minR, c.maxR, c.minQ, c.maxQ)
+	add := func(k hex.Hex) bool {
+		c.hexes.add(k)
+		return true
+	}
+	a.hexes.each(add)
+	b.hexes.each(add)
+	return c.pack()
 }
 
 // intersectionFn returns only those hexes that are in all areas.
 // this operation is commutative.
 func intersectionFn(a *Area, b *Area) *Area {
 
-	if a.boundsClean && b.boundsClean && !a.mightOverlap(b) {
+	if !a.mightOverlap(b) {
 		return NewArea()
 	}
 
-	c := make(map[hex.Hex]struct{})
-	for k := range b.hexes {
-		if _, ok := a.hexes[k]; ok {
-			c[k] = exists
+	if ba, ok := a.hexes.(*bitStore); ok {
+		if bb, ok := b.hexes.(*bitStore); ok {
+			if c := intersectionBits(ba, bb); c != nil {
+				return (&Area{hexes: c}).pack()
+			}
+			return NewArea()
 		}
 	}
-	return &Area{
-		hexes: c,
+	if ra, ok := a.hexes.(*runStore); ok {
+		if rb, ok := b.hexes.(*runStore); ok {
+			return (&Area{hexes: mergeRows(ra, rb, intersectRuns)}).pack()
+		}
 	}
+
+	// walk the smaller area and look up in the bigger one.
+	if a.Size() < b.Size() {
+		a, b = b, a
+	}
+	c := make(mapStore)
+	b.hexes.each(func(k hex.Hex) bool {
+		if a.hexes.has(k) {
+			c[k] = exists
+		}
+		return true
+	})
+	return (&Area{
+		hexes: c,
+	}).pack()
 }
 
-// subtractFn returns a, but with hexes shared by b removed.
+// subtractFn returns a copy of a, but with hexes shared by b removed.
 func subtractFn(a *Area, b *Area) *Area {
 
-	if a.boundsClean && b.boundsClean && !a.mightOverlap(b) {
-		return a
+	if !a.mightOverlap(b) {
+		return a.Clone()
 	}
 
-	c := make(map[hex.Hex]struct{})
-
-	for k := range a.hexes {
-		if _, ok := b.hexes[k]; !ok {
-			c[k] = exists
+	if ba, ok := a.hexes.(*bitStore); ok {
+		if bb, ok := b.hexes.(*bitStore); ok {
+			return (&Area{hexes: subtractBits(ba, bb)}).pack()
 		}
 	}
+	if ra, ok := a.hexes.(*runStore); ok {
+		if rb, ok := b.hexes.(*runStore); ok {
+			return (&Area{hexes: mergeRows(ra, rb, subtractRuns)}).pack()
+		}
+		// cut b's hexes out of the runs one at a time.
+		c := ra.clone()
+		b.hexes.each(func(k hex.Hex) bool {
+			c.remove(k)
+			return true
+		})
+		return (&Area{hexes: c}).pack()
+	}
+
+	c := newStore(a.Size(), a.minR, a.maxR, a.minQ, a.maxQ)
+	a.hexes.each(func(k hex.Hex) bool {
+		if !b.hexes.has(k) {
+			c.add(k)
+		}
+		return true
+	})
 
-	return &Area{
+	return (&Area{
 		hexes: c,
+	}).pack()
+}
+
+// xorFn returns the hexes that are in exactly one of the areas.
+// this operation is commutative.
+func xorFn(a *Area, b *Area) *Area {
+
+	if !a.mightOverlap(b) {
+		return unionFn(a, b)
 	}
+
+	minR, maxR := minInt(a.minR, b.minR), maxInt(a.maxR, b.maxR)
+	minQ, maxQ := minInt(a.minQ, b.minQ), maxInt(a.maxQ, b.maxQ)
+	dense := isDense(a.Size()+b.Size(), minR, maxR, minQ, maxQ)
+	if ba, ok := a.hexes.(*bitStore); ok && dense {
+		if bb, ok := b.hexes.(*bitStore); ok {
+			return (&Area{hexes: xorBits(ba, bb)}).pack()
+		}
+	}
+	if ra, ok := a.hexes.(*runStore); ok {
+		if rb, ok := b.hexes.(*runStore); ok {
+			return (&Area{hexes: mergeRows(ra, rb, xorRuns)}).pack()
+		}
+	}
+
+	c := newStore(a.Size()+b.Size(), minR, maxR, minQ, maxQ)
+	a.hexes.each(func(k hex.Hex) bool {
+		if !b.hexes.has(k) {
+			c.add(k)
+		}
+		return true
+	})
+	b.hexes.each(func(k hex.Hex) bool {
+		if !a.hexes.has(k) {
+			c.add(k)
+		}
+		return true
+	})
+	return (&Area{
+		hexes: c,
+	}).pack()
 }
@@ -0,0 +1,297 @@
+package area
+
+import (
+	"math/bits"
+
+	"github.com/erinpentecost/hex"
+)
+
+// bitStore keeps one bit for every hex in a bounding box.
+// It's much smaller and faster than a mapStore for areas
+// that fill most of their bounding box.
+//
+// Each row of R is a run of words. Word w holds the hexes with
+// Q from 64*w to 64*w+63, so words from different bitStores
+// line up and can be combined without shifting.
+type bitStore struct {
+	// rows covered by the box.
+	minR, maxR int64
+	// words covered by each row.
+	minW, maxW int64
+	// bits holds the rows one after another.
+	bits  []uint64
+	count int
+}
+
+// wordOf returns the word that holds column q.
+func wordOf(q int64) int64 {
+	return q >> 6
+}
+
+func newBitStore(minR, maxR, minQ, maxQ int64) *bitStore {
+	b := &bitStore{
+		minR: minR,
+		maxR: maxR,
+		minW: wordOf(minQ),
+		maxW: wordOf(maxQ),
+	}
+	b.bits = make([]uint64, (b.maxR-b.minR+1)*b.stride())
+	return b
+}
+
+// stride is the number of words in each row.
+func (b *bitStore) stride() int64 {
+	return b.maxW - b.minW + 1
+}
+
+// row returns the words for row r, which must be in the box.
+func (b *bitStore) row(r int64) []uint64 {
+	start := (r - b.minR) * b.stride()
+	return b.bits[start : start+b.stride()]
+}
+
+// locate finds the word and bit for h.
+// ok is false if h is outside the box.
+func (b *bitStore) locate(h hex.Hex) (index int64, mask uint64, ok bool) {
+	w := wordOf(h.Q)
+	if h.R < b.minR || h.R > b.maxR || w < b.minW || w > b.maxW {
+		return 0, 0, false
+	}
+	return (h.R-b.minR)*b.stride() + w - b.minW, 1 << uint64(h.Q&63), true
+}
+
+func (b *bitStore) has(h hex.Hex) bool {
+	i, mask, ok := b.locate(h)
+	return ok && b.bits[i]&mask != 0
+}
+
+func (b *bitStore) add(h hex.Hex) {
+	i, mask, ok := b.locate(h)
+	if !ok {
+		b.grow(h)
+		i, mask, _ = b.locate(h)
+	}
+	if b.bits[i]&mask == 0 {
+		b.bits[i] |= mask
+		b.count++
+	}
+}
+
+func (b *bitStore) remove(h hex.Hex) {
+	i, mask, ok := b.locate(h)
+	if ok && b.bits[i]&mask != 0 {
+		b.bits[i] &^= mask
+		b.count--
+	}
+}
+
+func (b *bitStore) size() int {
+	return b.count
+}
+
+func (b *bitStore) each(fn func(hex.Hex) bool) bool {
+	for r := b.minR; r <= b.maxR; r++ {
+		for i, word := range b.row(r) {
+			base := (b.minW + int64(i)) << 6
+			for word != 0 {
+				bit := bits.TrailingZeros64(word)
+				if !fn(hex.Hex{Q: base + int64(bit), R: r}) {
+					return false
+				}
+				word &= word - 1
+			}
+		}
+	}
+	return true
+}
+
+func (b *bitStore) clone() store {
+	c := *b
+	c.bits = make([]uint64, len(b.bits))
+	copy(c.bits, b.bits)
+	return &c
+}
+
+// grow makes the box big enough to hold h.
+// It grows by extra space in each direction it needs to,
+// so adding hexes one at a time doesn't copy every time.
+func (b *bitStore) grow(h hex.Hex) {
+	rows, words := b.maxR-b.minR+1, b.stride()
+	minR, maxR, minW, maxW := b.minR, b.maxR, b.minW, b.maxW
+	if w := wordOf(h.Q); w < minW {
+		minW = w - words/2
+	} else if w > maxW {
+		maxW = w + words/2
+	}
+	if h.R < minR {
+		minR = h.R - rows/2
+	} else if h.R > maxR {
+		maxR = h.R + rows/2
+	}
+
+	grown := &bitStore{minR: minR, maxR: maxR, minW: minW, maxW: maxW, count: b.count}
+	grown.bits = make([]uint64, (maxR-minR+1)*grown.stride())
+	grown.or(b)
+	*b = *grown
+}
+
+// or sets every bit in b that is set in x.
+// x's box must be inside b's box.
+func (b *bitStore) or(x *bitStore) {
+	for r := x.minR; r <= x.maxR; r++ {
+		dst := b.row(r)[x.minW-b.minW:]
+		for i, word := range x.row(r) {
+			dst[i] |= word
+		}
+	}
+}
+
+// recount updates count after bits were changed directly.
+func (b *bitStore) recount() {
+	b.count = 0
+	for _, word := range b.bits {
+		b.count += bits.OnesCount64(word)
+	}
+}
+
+// numRuns returns the number of runs of set bits in each row.
+func (b *bitStore) numRuns() int {
+	n := 0
+	for r := b.minR; r <= b.maxR; r++ {
+		// carry is the last bit of the word to the left.
+		carry := uint64(0)
+		for _, word := range b.row(r) {
+			// count the bits that start a run.
+			n += bits.OnesCount64(word &^ (word<<1 | carry))
+			carry = word >> 63
+		}
+	}
+	return n
+}
+
+// bounds finds the tightest box around the set bits.
+func (b *bitStore) bounds() (minR, maxR, minQ, maxQ int64, ok bool) {
+	if b.count == 0 {
+		return 0, 0, 0, 0, false
+	}
+	first := true
+	for r := b.minR; r <= b.maxR; r++ {
+		row := b.row(r)
+		lo, hi := -1, -1
+		for i, word := range row {
+			if word != 0 {
+				if lo < 0 {
+					lo = i
+				}
+				hi = i
+			}
+		}
+		if lo < 0 {
+			continue
+		}
+		rowMinQ := (b.minW+int64(lo))<<6 + int64(bits.TrailingZeros64(row[lo]))
+		rowMaxQ := (b.minW+int64(hi))<<6 + 63 - int64(bits.LeadingZeros64(row[hi]))
+		if first {
+			minR, minQ, maxQ = r, rowMinQ, rowMaxQ
+			first = false
+		}
+		maxR = r
+		minQ = minInt(minQ, rowMinQ)
+		maxQ = maxInt(maxQ, rowMaxQ)
+	}
+	return minR, maxR, minQ, maxQ, true
+}
+
+// unionBits returns a new bitStore with the hexes in a or b.
+func unionBits(a, b *bitStore) *bitStore {
+	out := &bitStore{
+		minR: minInt(a.minR, b.minR),
+		maxR: maxInt(a.maxR, b.maxR),
+		minW: minInt(a.minW, b.minW),
+		maxW: maxInt(a.maxW, b.maxW),
+	}
+	out.bits = make([]uint64, (out.maxR-out.minR+1)*out.stride())
+	out.or(a)
+	out.or(b)
+	out.recount()
+	return out
+}
+
+// xorBits returns a new bitStore with the hexes in exactly one of a and b.
+func xorBits(a, b *bitStore) *bitStore {
+	out := &bitStore{
+		minR: minInt(a.minR, b.minR),
+		maxR: maxInt(a.maxR, b.maxR),
+		minW: minInt(a.minW, b.minW),
+		maxW: maxInt(a.maxW, b.maxW),
+	}
+	out.bits = make([]uint64, (out.maxR-out.minR+1)*out.stride())
+	out.or(a)
+	for r := b.minR; r <= b.maxR; r++ {
+		dst := out.row(r)[b.minW-out.minW:]
+		for i, word := range b.row(r) {
+			dst[i] ^= word
+		}
+	}
+	out.recount()
+	return out
+}
+
+// intersectionBits returns a new bitStore with the hexes in both a and b,
+// or nil if their boxes don't overlap.
+func intersectionBits(a, b *bitStore) *bitStore {
+	out := &bitStore{
+		minR: maxInt(a.minR, b.minR),
+		maxR: minInt(a.maxR, b.maxR),
+		minW: maxInt(a.minW, b.minW),
+		maxW: minInt(a.maxW, b.maxW),
+	}
+	if out.minR > out.maxR || out.minW > out.maxW {
+		return nil
+	}
+	out.bits = make([]uint64, (out.maxR-out.minR+1)*out.stride())
+	for r := out.minR; r <= out.maxR; r++ {
+		dst := out.row(r)
+		ra := a.row(r)[out.minW-a.minW:]
+		rb := b.row(r)[out.minW-b.minW:]
+		for i := range dst {
+			dst[i] = ra[i] & rb[i]
+			out.count += bits.OnesCount64(dst[i])
+		}
+	}
+	return out
+}
+
+// subtractBits returns a new bitStore with the hexes in a but not in b.
+func subtractBits(a, b *bitStore) *bitStore {
+	out := a.clone().(*bitStore)
+	minR, maxR := maxInt(a.minR, b.minR), minInt(a.maxR, b.maxR)
+	minW, maxW := maxInt(a.minW, b.minW), minInt(a.maxW, b.maxW)
+	if minR > maxR || minW > maxW {
+		return out
+	}
+	for r := minR; r <= maxR; r++ {
+		dst := out.row(r)[minW-out.minW : maxW-out.minW+1]
+		rb := b.row(r)[minW-b.minW:]
+		for i := range dst {
+			out.count -= bits.OnesCount64(dst[i] & rb[i])
+			dst[i] &^= rb[i]
+		}
+	}
+	return out
+}
+
+// overlapBits returns the number of hexes in both a and b.
+func overlapBits(a, b *bitStore) int {
+	minR, maxR := maxInt(a.minR, b.minR), minInt(a.maxR, b.maxR)
+	minW, maxW := maxInt(a.minW, b.minW), minInt(a.maxW, b.maxW)
+	count := 0
+	for r := minR; r <= maxR; r++ {
+		ra := a.row(r)[minW-a.minW:]
+		rb := b.row(r)[minW-b.minW:]
+		for i := int64(0); i <= maxW-minW; i++ {
+			count += bits.OnesCount64(ra[i] & rb[i])
+		}
+	}
+	return count
+}
@@ -0,0 +1,280 @@
+package area
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/erinpentecost/hex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// asMap returns a copy of a that is always kept in a map.
+func asMap(a *Area) *Area {
+	c := make(mapStore, a.Size())
+	a.Each(func(h hex.Hex) bool {
+		c[h] = exists
+		return true
+	})
+	return (&Area{hexes: c}).ensureBounds()
+}
+
+// assertSameHexes checks that a and b hold exactly the same hexes,
+// without caring how they are stored.
+func assertSameHexes(t *testing.T, a, b *Area, msgAndArgs ...interface{}) {
+	ok := a.Size() == b.Size()
+	a.Each(func(h hex.Hex) bool {
+		ok = ok && b.ContainsHexes(h)
+		return ok
+	})
+	assert.True(t, ok, msgAndArgs...)
+}
+
+// asBits returns a copy of a that is always kept in a bitset.
+func asBits(a *Area) *Area {
+	a.ensureBounds()
+	c := newBitStore(a.minR, a.maxR, a.minQ, a.maxQ)
+	a.Each(func(h hex.Hex) bool {
+		c.add(h)
+		return true
+	})
+	return (&Area{hexes: c}).ensureBounds()
+}
+
+func isBits(a *Area) bool {
+	_, ok := a.hexes.(*bitStore)
+	return ok
+}
+
+func TestStoreChoice(t *testing.T) {
+	assert.False(t, isBits(BigHex(hex.Origin(), 3)))
+	assert.False(t, isBits(Ring(hex.Origin(), 500)))
+	assert.True(t, isBits(BigHex(hex.Origin(), 30)))
+	assert.True(t, isBits(Rectangle(hex.Hex{Q: -40, R: -40}, hex.Hex{Q: 40, R: 40})))
+
+	// shrinking a dense area puts it back in a map.
+	small := BigHex(hex.Origin(), 30).Subtract(BigHex(hex.Origin(), 29)).Build()
+	assert.False(t, isBits(small))
+	assert.Equal(t, 180, small.Size())
+}
+
+func TestBitStoreAddRemove(t *testing.T) {
+	rng := rand.New(rand.NewSource(23))
+	b := newBitStore(0, 0, 0, 0)
+	expected := make(map[hex.Hex]bool)
+	for i := 0; i < 5000; i++ {
+		// cross word boundaries and negative coordinates.
+		h := hex.Hex{Q: rng.Int63n(301) - 150, R: rng.Int63n(41) - 20}
+		if rng.Intn(3) == 0 {
+			b.remove(h)
+			delete(expected, h)
+		} else {
+			b.add(h)
+			expected[h] = true
+		}
+		require.Equal(t, len(expected), b.size())
+	}
+
+	visited := 0
+	b.each(func(h hex.Hex) bool {
+		require.True(t, expected[h], h)
+		visited++
+		return true
+	})
+	assert.Equal(t, len(expected), visited)
+
+	for h := range expected {
+		assert.True(t, b.has(h))
+	}
+	assert.False(t, b.has(hex.Hex{Q: 1000, R: 1000}))
+
+	minR, maxR, minQ, maxQ, ok := b.bounds()
+	require.True(t, ok)
+	bf := boundsFinder{}
+	for h := range expected {
+		h := h
+		bf.visit(&h)
+	}
+	assert.Equal(t, []int64{bf.minR, bf.maxR, bf.minQ, bf.maxQ}, []int64{minR, maxR, minQ, maxQ})
+}
+
+func TestBitStoreMatchesMap(t *testing.T) {
+	shapes := []*Area{
+		BigHex(hex.Hex{Q: 3, R: -2}, 40),
+		BigHex(hex.Hex{Q: 70, R: 10}, 35),
+		Rectangle(hex.Hex{Q: -60, R: -20}, hex.Hex{Q: 20, R: 30}),
+		Circle(hex.Hex{Q: -64, R: 64}, 30),
+		BigHex(hex.Hex{Q: 1000, R: 1000}, 20),
+	}
+	for _, s := range shapes {
+		require.True(t, isBits(s))
+	}
+
+	for i, a := range shapes {
+		ma := asMap(a)
+		require.False(t, isBits(ma))
+		requireSameBounds(t, a)
+
+		for j, b := range shapes {
+			mb := asMap(b)
+
+			assertSameHexes(t, ma.Union(mb).Build(), a.Union(b).Build(), "union %d %d", i, j)
+			assertSameHexes(t, ma.Intersection(mb).Build(), a.Intersection(b).Build(), "intersection %d %d", i, j)
+			assertSameHexes(t, ma.Subtract(mb).Build(), a.Subtract(b).Build(), "subtract %d %d", i, j)
+			assertSameHexes(t, ma.Xor(mb).Build(), a.Xor(b).Build(), "xor %d %d", i, j)
+
+			// mixed stores work too.
+			assertSameHexes(t, ma.Union(mb).Build(), ma.Union(b).Build(), "mixed union %d %d", i, j)
+			assertSameHexes(t, ma.Subtract(mb).Build(), a.Subtract(mb).Build(), "mixed subtract %d %d", i, j)
+
+			assert.Equal(t, ma.CheckBounding(mb), a.CheckBounding(b), "bounding %d %d", i, j)
+			assert.Equal(t, ma.CheckBounding(mb), a.CheckBounding(mb), "mixed bounding %d %d", i, j)
+		}
+	}
+}
+
+func TestBitStoreTransform(t *testing.T) {
+	a := BigHex(hex.Hex{Q: 5, R: -9}, 40)
+	require.True(t, isBits(a))
+	rotated := a.Rotate(hex.Hex{Q: 100, R: 3}, 2).Build()
+	assert.True(t, isBits(rotated))
+	assert.True(t, BigHex(hex.Hex{Q: 5, R: -9}.Rotate(hex.Hex{Q: 100, R: 3}, 2), 40).Equals(rotated))
+	requireSameBounds(t, rotated)
+}
+
+func TestBitStoreEncoding(t *testing.T) {
+	a := BigHex(hex.Hex{Q: -70, R: 3}, 30)
+	require.True(t, isBits(a))
+
+	data, err := a.MarshalBinary()
+	require.NoError(t, err)
+	var b Area
+	require.NoError(t, b.UnmarshalBinary(data))
+	assert.True(t, a.Equals(&b))
+}
+
+func TestZeroArea(t *testing.T) {
+	var z Area
+	assert.Equal(t, 0, z.Size())
+	assert.Empty(t, z.Slice())
+	assert.False(t, z.ContainsHexes(hex.Origin()))
+	assert.Equal(t, 0, z.Clone().Size())
+	z.Each(func(h hex.Hex) bool {
+		t.Fatal("zero area should be empty")
+		return true
+	})
+}
+
+// millionHexes is a BigHex with just over a million hexes.
+const millionHexes = 577
+
+func benchmarkCSG(b *testing.B, fn func(a, c *Area) *Area) {
+	x := asBits(BigHex(hex.Origin(), millionHexes))
+	y := asBits(BigHex(hex.Hex{Q: 200, R: -100}, millionHexes))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fn(x, y)
+	}
+}
+
+// benchmarkMapCSG runs fn on the same shapes kept in plain maps,
+// to compare against.
+func benchmarkMapCSG(b *testing.B, fn func(a, c mapStore) mapStore) {
+	x := asMap(BigHex(hex.Origin(), millionHexes)).hexes.(mapStore)
+	y := asMap(BigHex(hex.Hex{Q: 200, R: -100}, millionHexes)).hexes.(mapStore)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fn(x, y)
+	}
+}
+
+// mapUnion, mapIntersection and mapSubtract do CSG
+// one hex at a time, without ever leaving a map.
+func mapUnion(a, b mapStore) mapStore {
+	c := make(mapStore, len(a)+len(b))
+	for k := range a {
+		c[k] = exists
+	}
+	for k := range b {
+		c[k] = exists
+	}
+	return c
+}
+
+func mapIntersection(a, b mapStore) mapStore {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	c := make(mapStore)
+	for k := range b {
+		if a.has(k) {
+			c[k] = exists
+		}
+	}
+	return c
+}
+
+func mapSubtract(a, b mapStore) mapStore {
+	c := make(mapStore)
+	for k := range a {
+		if !b.has(k) {
+			c[k] = exists
+		}
+	}
+	return c
+}
+
+func BenchmarkUnionBits(b *testing.B)        { benchmarkCSG(b, unionFn) }
+func BenchmarkUnionMap(b *testing.B)         { benchmarkMapCSG(b, mapUnion) }
+func BenchmarkIntersectionBits(b *testing.B) { benchmarkCSG(b, intersectionFn) }
+func BenchmarkIntersectionMap(b *testing.B)  { benchmarkMapCSG(b, mapIntersection) }
+func BenchmarkSubtractBits(b *testing.B)     { benchmarkCSG(b, subtractFn) }
+func BenchmarkSubtractMap(b *testing.B)      { benchmarkMapCSG(b, mapSubtract) }
+
+func TestBitStoreFarAway(t *testing.T) {
+	block := make([]hex.Hex, 0)
+	for q := int64(0); q < 40; q++ {
+		for r := int64(0); r < 40; r++ {
+			block = append(block, hex.Hex{Q: q, R: r})
+		}
+	}
+	a := NewArea(block...)
+	require.True(t, isBits(a))
+
+	// one far away hex moves the area out of the bitset
+	// instead of growing the bitset to cover it.
+	far := hex.Hex{R: 1 << 40}
+	a.Add(far)
+	assert.False(t, isBits(a))
+	assert.Equal(t, len(block)+1, a.Size())
+	assert.True(t, a.ContainsHexes(far))
+	requireSameBounds(t, a)
+
+	// same for CSG between far apart bitsets.
+	b := NewArea(block...).Translate(hex.Hex{Q: 1 << 40}).Build()
+	require.True(t, isBits(b))
+	assert.Equal(t, 2*len(block), NewArea(block...).Union(b).Build().Size())
+	assert.Equal(t, 2*len(block), NewArea(block...).Xor(b).Build().Size())
+
+	// tall and wide bitsets that overlap, but whose union isn't dense.
+	tall, wide := NewArea(), NewArea()
+	for i := int64(-5000); i < 5000; i++ {
+		tall.Add(hex.Hex{Q: 0, R: i}, hex.Hex{Q: 1, R: i})
+		wide.Add(hex.Hex{Q: i, R: 0}, hex.Hex{Q: i, R: 1})
+	}
+	tall, wide = asBits(tall), asBits(wide)
+	assert.Equal(t, 40000-8, tall.Xor(wide).Build().Size())
+	assert.Equal(t, 40000-4, tall.Union(wide).Build().Size())
+}
+
+func TestIsDenseOverflow(t *testing.T) {
+	assert.False(t, isDense(1<<20, math.MinInt64, math.MaxInt64, 0, 0))
+	assert.False(t, isDense(1<<20, 0, 0, math.MinInt64, math.MaxInt64))
+	assert.False(t, isDense(1<<20, 0, 1<<32, 0, 1<<32))
+	assert.False(t, isDense(math.MaxInt64, 0, math.MaxInt64-1, 0, math.MaxInt64-1))
+	assert.True(t, isDense(math.MaxInt64, 0, 10, 0, 10))
+	assert.True(t, isDense(1<<20, 0, 1<<10, 0, 1<<10))
+}
@@ -1,8 +1,6 @@
 package area
 
 import (
-	"sync"
-
 	"github.com/erinpentecost/hex"
 )
 
@@ -33,10 +31,11 @@ func (a *Area) CheckBounding(b *Area) Bounding {
 	// use bounding boxes to determine if there is any overlap
 	// if there is, do a finer check. otherwise return Distinct.
 
-	if len(a.hexes) == 0 || len(b.hexes) == 0 {
+	if a.Size() == 0 || b.Size() == 0 {
 		return Undefined
 	}
 
+	a, b, _ = onSameTopology(a, b)
 	if a.mightOverlap(b) {
 		return a.checkFineBounding(b)
 	}
@@ -46,7 +45,7 @@ func (a *Area) CheckBounding(b *Area) Bounding {
 // mightOverlap returns true if the bounding boxes of a and b
 // might overlap.
 func (a *Area) mightOverlap(b *Area) bool {
-	if len(a.hexes) == 0 || len(b.hexes) == 0 {
+	if a.Size() == 0 || b.Size() == 0 {
 		return false
 	}
 	a.ensureBounds()
@@ -67,36 +66,14 @@ func contains(min, max, test int64) bool {
 }
 
 func (a *Area) checkFineBounding(b *Area) Bounding {
-	if len(a.hexes) == 0 || len(b.hexes) == 0 {
+	if a.Size() == 0 || b.Size() == 0 {
 		return Undefined
 	}
 
-	contains := true
-	containedBy := true
-	overlap := false
-
-	wg := sync.WaitGroup{}
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for k := range a.hexes {
-			if _, ok := b.hexes[k]; ok {
-				overlap = true
-			} else {
-				containedBy = false
-			}
-		}
-	}()
-
-	for k := range b.hexes {
-		if _, ok := a.hexes[k]; ok {
-			overlap = true
-		} else {
-			contains = false
-		}
-	}
-
-	wg.Wait()
+	shared := overlapCount(a, b)
+	overlap := shared > 0
+	contains := shared == b.Size()
+	containedBy := shared == a.Size()
 
 	if !overlap {
 		return Distinct
@@ -114,16 +91,52 @@ func (a *Area) checkFineBounding(b *Area) Bounding {
 	return Overlap
 }
 
+// overlapCount returns the number of hexes in both a and b.
+func overlapCount(a, b *Area) int {
+	if ba, ok := a.hexes.(*bitStore); ok {
+		if bb, ok := b.hexes.(*bitStore); ok {
+			return overlapBits(ba, bb)
+		}
+	}
+	ra, aRuns := a.hexes.(*runStore)
+	rb, bRuns := b.hexes.(*runStore)
+	switch {
+	case aRuns && bRuns:
+		return overlapRuns(ra, rb)
+	case aRuns:
+		// look up the other side's hexes in the runs,
+		// rather than visiting every hex in the runs.
+	case bRuns:
+		a, b = b, a
+	default:
+		// walk the smaller area and look up in the bigger one.
+		if a.Size() < b.Size() {
+			a, b = b, a
+		}
+	}
+
+	count := 0
+	b.hexes.each(func(k hex.Hex) bool {
+		if a.hexes.has(k) {
+			count++
+		}
+		return true
+	})
+	return count
+}
+
 type boundsFinder struct {
-	empty bool
-	minR  int64
-	maxR  int64
-	minQ  int64
-	maxQ  int64
+	// seen is true once any hex has been visited.
+	seen bool
+	minR int64
+	maxR int64
+	minQ int64
+	maxQ int64
 }
 
 func (b *boundsFinder) visit(p *hex.Hex) {
-	if b.empty {
+	if !b.seen {
+		b.seen = true
 		b.minR = p.R
 		b.maxR = p.R
 		b.minQ = p.Q
@@ -138,7 +151,7 @@ func (b *boundsFinder) visit(p *hex.Hex) {
 }
 
 func (b *boundsFinder) applyTo(a *Area) *Area {
-	if b.empty {
+	if !b.seen {
 		a.minR = 0
 		a.maxR = 0
 		a.minQ = 0
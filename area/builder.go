@@ -18,17 +18,51 @@ type Builder interface {
 	Intersection(b Builder) Builder
 	// Subtract returns all those hexes in the first area that are not in the second.
 	Subtract(b Builder) Builder
+	// Xor returns the hexes that are in exactly one of the areas.
+	Xor(b Builder) Builder
 	// Rotate rotates the area about some pivot some number of sides.
 	Rotate(pivot hex.Hex, direction int) Builder
+	// Reflect mirrors the area across an axis that passes through pivot.
+	Reflect(pivot hex.Hex, axis hex.Axis) Builder
 	// Translate adds some offset to the area.
 	Translate(offste hex.Hex) Builder
-	// Transform applies a transformation hex to each hex in the area.
+	// Transform applies a transformation to each hex in the area.
 	//
 	// This doesn't infill scaling transformations!
-	Transform(t [4][4]int64) Builder
+	Transform(t hex.Transform) Builder
 }
 
 // NewBuilder creates a new area builder containing zero or more hexes to start with.
 func NewBuilder(hexes ...hex.Hex) Builder {
 	return NewArea(hexes...)
 }
+
+// UnionAll combines all hexes in all the builders.
+//
+// The builders are joined as a balanced tree, so building
+// them takes fewer steps than a long chain of Unions.
+func UnionAll(builders ...Builder) Builder {
+	return balanced(builders, union)
+}
+
+// IntersectAll returns only those hexes shared by all the builders.
+// It returns an empty area if there are no builders.
+//
+// The builders are joined as a balanced tree, so building
+// them takes fewer steps than a long chain of Intersections.
+func IntersectAll(builders ...Builder) Builder {
+	return balanced(builders, intersection)
+}
+
+// balanced joins builders with an associative operation
+// by splitting them in half over and over.
+func balanced(builders []Builder, opt operation) Builder {
+	switch len(builders) {
+	case 0:
+		return NewArea()
+	case 1:
+		return builders[0]
+	}
+	mid := len(builders) / 2
+	return newNode(balanced(builders[:mid], opt), balanced(builders[mid:], opt), opt)
+}
@@ -2,6 +2,7 @@ package area
 
 import (
 	"fmt"
+	"math"
 	"testing"
 
 	"github.com/erinpentecost/hex"
@@ -158,3 +159,361 @@ func TestBounding(t *testing.T) {
 		test.assertBound(t, fmt.Sprintf("%d", i))
 	}
 }
+
+func TestOffsetRectangle(t *testing.T) {
+	corners := []hex.Hex{{Q: 3, R: -5}, {Q: -2, R: 4}}
+	for _, parity := range []hex.OffsetParity{hex.OddR, hex.EvenR, hex.OddQ, hex.EvenQ} {
+		t.Run(parity.String(), func(t *testing.T) {
+			rect := OffsetRectangle(parity, corners...)
+			a := corners[0].ToOffset(parity)
+			b := corners[1].ToOffset(parity)
+			width := maxInt(a.Col, b.Col) - minInt(a.Col, b.Col) + 1
+			height := maxInt(a.Row, b.Row) - minInt(a.Row, b.Row) + 1
+			require.Equal(t, int(width*height), rect.Size())
+
+			for _, o := range rect.OffsetCoords(parity) {
+				require.True(t, contains(minInt(a.Col, b.Col), maxInt(a.Col, b.Col), o.Col), "col out of range: %s", o)
+				require.True(t, contains(minInt(a.Row, b.Row), maxInt(a.Row, b.Row), o.Row), "row out of range: %s", o)
+			}
+			require.True(t, rect.Equals(NewAreaFromOffset(parity, rect.OffsetCoords(parity)...)))
+		})
+	}
+}
+
+func TestRectangleNegativeRows(t *testing.T) {
+	rect := Rectangle(hex.Hex{Q: 2, R: -4}, hex.Hex{Q: 0, R: 3})
+	// In odd-r layout, the leftmost hex of each odd row sits half a hex
+	// to the right of the leftmost hex of each even row.
+	leftmost := make(map[int64]float64)
+	for _, h := range rect.Slice() {
+		x, _ := h.ToHexFractional().ToCartesian()
+		if cur, ok := leftmost[h.R]; !ok || x < cur {
+			leftmost[h.R] = x
+		}
+	}
+	even := leftmost[0]
+	for r, x := range leftmost {
+		expected := even
+		if r&1 == 1 {
+			expected = even + math.Sqrt(3)/2
+		}
+		assert.InDelta(t, expected, x, 1e-9, "row %d", r)
+	}
+}
+
+func TestBoundaryEdges(t *testing.T) {
+	for radius := int64(0); radius < 5; radius++ {
+		a := BigHex(hex.Hex{Q: 3, R: -1}, radius)
+		edges := a.BoundaryEdges()
+		require.Len(t, edges, int(6*(2*radius+1)), "radius %d", radius)
+
+		seen := make(map[hex.HexEdge]struct{})
+		for _, e := range edges {
+			seen[e] = struct{}{}
+			h := e.Hexes()
+			require.True(t, a.ContainsHexes(h[0]) != a.ContainsHexes(h[1]), "edge %s is not on the boundary", e)
+		}
+		require.Len(t, seen, len(edges))
+	}
+
+	assert.Empty(t, NewArea().BoundaryEdges())
+}
+
+func TestInteriorVertices(t *testing.T) {
+	for radius := int64(0); radius < 5; radius++ {
+		a := BigHex(hex.Hex{Q: -2, R: 7}, radius)
+		vertices := a.InteriorVertices()
+		require.Len(t, vertices, int(6*radius*radius), "radius %d", radius)
+
+		seen := make(map[hex.HexVertex]struct{})
+		for _, v := range vertices {
+			seen[v] = struct{}{}
+			h := v.Hexes()
+			require.True(t, a.ContainsHexes(h[:]...), "vertex %s is not interior", v)
+		}
+		require.Len(t, seen, len(vertices))
+	}
+}
+
+func TestRingAndSpiral(t *testing.T) {
+	center := hex.Hex{Q: 2, R: 5}
+	for radius := int64(0); radius < 6; radius++ {
+		ring := Ring(center, radius)
+		expected := BigHex(center, radius).Subtract(BigHex(center, radius-1)).Build()
+		assert.True(t, expected.Equals(ring), "radius %d\nexpected=%s\nactual=  %s", radius, expected.String(), ring.String())
+		assert.True(t, BigHex(center, radius).Equals(Spiral(center, radius)), "radius %d", radius)
+	}
+}
+
+func TestReflect(t *testing.T) {
+	shape := Polygon(hex.Hex{Q: 0, R: 0}, hex.Hex{Q: 4, R: -1}, hex.Hex{Q: 3, R: 2}).Build()
+	pivot := hex.Hex{Q: -2, R: 3}
+	for _, axis := range []hex.Axis{hex.AxisQ, hex.AxisR, hex.AxisS, hex.AxisQR, hex.AxisRS, hex.AxisSQ} {
+		t.Run(axis.String(), func(t *testing.T) {
+			expected := NewArea()
+			for _, h := range shape.Slice() {
+				expected = expected.Union(NewArea(h.Subtract(pivot).Reflect(axis).Add(pivot))).Build()
+			}
+			reflected := shape.Reflect(pivot, axis).Build()
+			assert.True(t, expected.Equals(reflected), "expected=%s\nactual=  %s", expected.String(), reflected.String())
+
+			// chained transforms are combined
+			twice := shape.Reflect(pivot, axis).Reflect(pivot, axis).Build()
+			assert.True(t, shape.Equals(twice), "expected=%s\nactual=  %s", shape.String(), twice.String())
+
+			moved := shape.Translate(hex.Hex{Q: 5, R: -5}).Reflect(pivot, axis).Rotate(pivot, 2).Build()
+			expectedMoved := expected.Translate(hex.Hex{Q: 5, R: -5}.Reflect(axis)).Rotate(pivot, 2).Build()
+			assert.True(t, expectedMoved.Equals(moved), "expected=%s\nactual=  %s", expectedMoved.String(), moved.String())
+		})
+	}
+}
+
+func TestStyledLine(t *testing.T) {
+	a := hex.Hex{Q: -3, R: 1}
+	b := hex.Hex{Q: 3, R: -2}
+	c := hex.Hex{Q: 0, R: 4}
+
+	assert.True(t, Line(a, b, c).Equals(StyledLine(LerpLine, a, b, c)))
+	assert.True(t, StyledLine(NudgedLine, a, b, c).Equals(StyledLine(NudgedLine, c, b, a)))
+	assert.True(t, StyledLine(SupercoverLine, a, b, c).Equals(StyledLine(SupercoverLine, c, b, a)))
+
+	super := StyledLine(SupercoverLine, a, b, c)
+	assert.True(t, super.ContainsHexes(Line(a, b, c).Slice()...))
+	assert.True(t, super.ContainsHexes(StyledLine(NudgedLine, a, b, c).Slice()...))
+
+	assert.Len(t, StyledLine(SupercoverLine, hex.Origin(), hex.DiagonalDirection(0)).Slice(), 4)
+}
+
+func TestWrap(t *testing.T) {
+	cylinder := hex.Cylinder{Width: 10}
+
+	// a line that runs off the east side comes back in on the west.
+	line := Line(hex.Hex{Q: 7, R: 0}, hex.Hex{Q: 12, R: 0}).Wrap(cylinder)
+	expected := NewArea(hex.Hex{Q: 7}, hex.Hex{Q: 8}, hex.Hex{Q: 9}, hex.Hex{Q: 0}, hex.Hex{Q: 1}, hex.Hex{Q: 2})
+	assert.True(t, expected.Equals(line), "expected=%s\nactual=%s", expected, line)
+
+	// shapes bigger than the world overlap themselves.
+	assert.Equal(t, 10, Line(hex.Hex{Q: 0, R: 0}, hex.Hex{Q: 30, R: 0}).Wrap(cylinder).Size())
+
+	torus := hex.HexTorus{Radius: 4}
+	world := BigHex(hex.Origin(), 4)
+	assert.True(t, world.Equals(BigHex(hex.Hex{Q: 20, R: -3}, 9).Wrap(torus)))
+	for _, k := range BigHex(hex.Hex{Q: 4, R: 0}, 2).Wrap(torus).Slice() {
+		assert.True(t, world.ContainsHexes(k))
+	}
+	assert.Equal(t, 19, BigHex(hex.Hex{Q: 4, R: 0}, 2).Wrap(torus).Size())
+}
+
+func TestBuilderTransform(t *testing.T) {
+	orig := BigHex(hex.Hex{Q: 3, R: -1}, 3).Union(Line(hex.Origin(), hex.Hex{Q: 8, R: 2})).Build()
+	pivot := hex.Hex{Q: 1, R: 1}
+
+	tr := hex.RotateTransform(pivot, 2).
+		Compose(hex.TranslateTransform(hex.Hex{Q: -5, R: 4})).
+		Compose(hex.ReflectTransform(pivot, hex.AxisR))
+	chained := orig.Rotate(pivot, 2).Translate(hex.Hex{Q: -5, R: 4}).Reflect(pivot, hex.AxisR).Build()
+	transformed := orig.Transform(tr).Build()
+	assert.True(t, chained.Equals(transformed), "expected=%s\nactual=%s", chained, transformed)
+
+	inv, err := tr.Inverse()
+	require.NoError(t, err)
+	assert.True(t, orig.Equals(transformed.Transform(inv).Build()))
+}
+
+func TestEach(t *testing.T) {
+	a := BigHex(hex.Hex{Q: 2, R: 2}, 5)
+
+	visited := make([]hex.Hex, 0)
+	a.Each(func(h hex.Hex) bool {
+		visited = append(visited, h)
+		return true
+	})
+	assert.ElementsMatch(t, a.Slice(), visited)
+
+	count := 0
+	a.Each(func(h hex.Hex) bool {
+		count++
+		return count < 10
+	})
+	assert.Equal(t, 10, count)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		total := int64(0)
+		a.Each(func(h hex.Hex) bool {
+			total += h.Q
+			return true
+		})
+	})
+	assert.Zero(t, allocs)
+}
+
+func TestXor(t *testing.T) {
+	a := BigHex(hex.Origin(), 3)
+	b := BigHex(hex.Hex{Q: 2, R: 0}, 3)
+	expected := a.Subtract(b).Union(b.Subtract(a)).Build()
+	assert.True(t, expected.Equals(a.Xor(b).Build()))
+	assert.True(t, expected.Equals(b.Xor(a).Build()))
+
+	// xor with itself is empty, and with nothing is itself.
+	assert.Equal(t, 0, a.Xor(a).Build().Size())
+	assert.True(t, a.Equals(a.Xor(NewArea()).Build()))
+	assert.True(t, a.Equals(NewArea().Xor(a).Build()))
+
+	far := BigHex(hex.Hex{Q: 50, R: 50}, 1)
+	assert.Equal(t, a.Size()+far.Size(), a.Xor(far).Build().Size())
+}
+
+func TestUnionAndIntersectAll(t *testing.T) {
+	builders := make([]Builder, 0)
+	for i := int64(0); i < 13; i++ {
+		builders = append(builders, BigHex(hex.Hex{Q: i, R: -i / 2}, 8))
+	}
+
+	chainedUnion := builders[0]
+	chainedIntersection := builders[0]
+	for _, b := range builders[1:] {
+		chainedUnion = chainedUnion.Union(b)
+		chainedIntersection = chainedIntersection.Intersection(b)
+	}
+
+	union := UnionAll(builders...)
+	intersection := IntersectAll(builders...)
+	assert.True(t, chainedUnion.Build().Equals(union.Build()))
+	assert.True(t, chainedIntersection.Build().Equals(intersection.Build()))
+	assert.LessOrEqual(t, height(union), int64(5))
+	assert.LessOrEqual(t, height(intersection), int64(5))
+
+	assert.Equal(t, 0, UnionAll().Build().Size())
+	assert.Equal(t, 0, IntersectAll().Build().Size())
+	assert.Equal(t, builders[3], UnionAll(builders[3]))
+}
+
+func TestBalancedChains(t *testing.T) {
+	hexes := BigHex(hex.Origin(), 5).Slice()
+	union := NewBuilder()
+	xored := NewBuilder()
+	for _, h := range hexes {
+		union = union.Union(NewArea(h))
+		xored = xored.Xor(NewArea(h))
+	}
+	// a chain of n unions is only about 2*log2(n) high.
+	limit := int64(2*math.Log2(float64(len(hexes)))) + 2
+	assert.LessOrEqual(t, height(union), limit)
+	assert.LessOrEqual(t, height(xored), limit)
+	assert.True(t, BigHex(hex.Origin(), 5).Equals(union.Build()))
+	assert.True(t, BigHex(hex.Origin(), 5).Equals(xored.Build()))
+
+	// subtractions can't be rotated.
+	sub := BigHex(hex.Origin(), 5).Build()
+	var subtracted Builder = sub
+	for _, h := range hexes[:10] {
+		subtracted = subtracted.Subtract(NewArea(h))
+	}
+	assert.Equal(t, int64(11), height(subtracted))
+	assert.Equal(t, sub.Size()-10, subtracted.Build().Size())
+}
+
+func TestBuildersAreImmutable(t *testing.T) {
+	base := BigHex(hex.Origin(), 2)
+	moved := base.Translate(hex.Hex{Q: 10})
+	before := moved.Build().Clone()
+
+	// chaining another transform leaves the old builder alone.
+	moved.Rotate(hex.Origin(), 3).Build()
+	assert.True(t, before.Equals(moved.Build()))
+
+	// so do rotations that share subtrees.
+	chain := NewBuilder()
+	for i := int64(0); i < 6; i++ {
+		chain = chain.Union(NewArea(hex.Hex{Q: i}).Translate(hex.Hex{R: i}))
+	}
+	built := chain.Build().Clone()
+	chain.Union(NewArea(hex.Hex{Q: 99})).Translate(hex.Hex{Q: 5}).Build()
+	assert.True(t, built.Equals(chain.Build()))
+}
+
+func TestCSGAcrossSeam(t *testing.T) {
+	cylinder := hex.Cylinder{Width: 10}
+	world := BigHex(hex.Origin(), 12).Build().Wrap(cylinder)
+	east, west := hex.Hex{Q: 9, R: 0}, hex.Hex{Q: 1, R: 0}
+
+	// near finds the hexes in the world within radius of both centers,
+	// going around the cylinder.
+	near := func(radius int64, centers ...hex.Hex) *Area {
+		found := NewArea()
+		world.Each(func(h hex.Hex) bool {
+			for _, c := range centers {
+				if cylinder.DistanceTo(c, h) > radius {
+					return true
+				}
+			}
+			found.Add(h)
+			return true
+		})
+		return found
+	}
+
+	e := BigHex(east, 2).Build().Wrap(cylinder)
+	w := BigHex(west, 2).Build().Wrap(cylinder)
+	assert.True(t, near(2, east).Equals(e))
+
+	// wrapped areas combine across the seam.
+	shared := e.Intersection(w).Build()
+	require.NotZero(t, shared.Size())
+	assert.True(t, near(2, east, west).Equals(shared))
+	assert.Equal(t, e.Size()-shared.Size(), e.Subtract(w).Build().Size())
+	assert.Equal(t, e.Size()+w.Size()-2*shared.Size(), e.Xor(w).Build().Size())
+	assert.Equal(t, cylinder, shared.Topology())
+
+	// planar areas are wrapped when they meet a wrapped one,
+	// on either side.
+	assert.True(t, shared.Equals(e.Intersection(BigHex(west, 2)).Build()))
+	assert.True(t, shared.Equals(BigHex(west, 2).Intersection(e).Build()))
+	assert.True(t, e.Subtract(w).Build().Equals(e.Subtract(BigHex(west, 2)).Build()))
+	assert.True(t, e.Xor(w).Build().Equals(BigHex(west, 2).Xor(e).Build()))
+	assert.Equal(t, Overlap, e.CheckBounding(BigHex(west, 2)))
+	assert.Equal(t, Overlap, BigHex(west, 2).CheckBounding(e))
+
+	// but planar areas alone don't know about the seam.
+	assert.Zero(t, BigHex(east, 2).Intersection(BigHex(west, 2)).Build().Size())
+	assert.Equal(t, hex.Planar{}, BigHex(east, 2).Topology())
+}
+
+func TestWrappedAreas(t *testing.T) {
+	cylinder := hex.Cylinder{Width: 10}
+
+	// a band all the way around the cylinder has no edges at the seam.
+	band := Rectangle(hex.Hex{Q: 0, R: 0}, hex.Hex{Q: 9, R: 0}).Wrap(cylinder)
+	assert.Len(t, band.BoundaryEdges(), 2*10*2)
+	assert.Len(t, Rectangle(hex.Hex{Q: 0, R: 0}, hex.Hex{Q: 9, R: 0}).BoundaryEdges(), 2*10*2+2)
+	for _, e := range band.BoundaryEdges() {
+		hexes := e.Hexes()
+		assert.NotEqual(t, hexes[0].R, hexes[1].R, "%v is along the band", e)
+	}
+
+	// two rows around the cylinder share a vertex between every pair of hexes.
+	rows := Rectangle(hex.Hex{Q: 0, R: 0}, hex.Hex{Q: 9, R: 1}).Wrap(cylinder)
+	assert.Len(t, rows.InteriorVertices(), 2*10)
+
+	// hexes are wrapped on the way in.
+	a := NewArea(hex.Hex{Q: 2}).Wrap(cylinder)
+	a.Add(hex.Hex{Q: 13})
+	assert.True(t, a.ContainsHexes(hex.Hex{Q: 3}, hex.Hex{Q: 23}, hex.Hex{Q: -8}))
+	assert.ElementsMatch(t, []hex.Hex{{Q: 2}, {Q: 3}}, a.Slice())
+	a.Remove(hex.Hex{Q: -5})
+	assert.ElementsMatch(t, []hex.Hex{{Q: 2}, {Q: 3}}, a.Slice())
+	a.Remove(hex.Hex{Q: 12})
+	assert.ElementsMatch(t, []hex.Hex{{Q: 3}}, a.Slice())
+	assert.Equal(t, cylinder, a.Clone().Topology())
+
+	// moving past the seam wraps around.
+	moved := a.Translate(hex.Hex{Q: 8}).Build()
+	assert.ElementsMatch(t, []hex.Hex{{Q: 1}}, moved.Slice())
+	assert.Equal(t, cylinder, moved.Topology())
+
+	// decoding doesn't keep the topology.
+	b, err := a.MarshalBinary()
+	require.NoError(t, err)
+	require.NoError(t, a.UnmarshalBinary(b))
+	assert.Equal(t, hex.Planar{}, a.Topology())
+}
@@ -0,0 +1,286 @@
+package area
+
+import (
+	"encoding"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/erinpentecost/hex"
+)
+
+var (
+	_ encoding.TextMarshaler   = (*Area)(nil)
+	_ encoding.TextUnmarshaler = (*Area)(nil)
+	_ json.Marshaler           = (*Area)(nil)
+	_ json.Unmarshaler         = (*Area)(nil)
+
+	_ encoding.BinaryMarshaler   = (*Area)(nil)
+	_ encoding.BinaryUnmarshaler = (*Area)(nil)
+)
+
+// binaryVersion is the first byte of the binary encoding.
+// Bump this if the layout changes.
+const binaryVersion byte = 1
+
+// ErrUnknownBinaryVersion is returned when decoding a binary
+// area made by an unknown version of the encoder.
+var ErrUnknownBinaryVersion = errors.New("unknown area binary encoding version")
+
+// ErrBadBinary is returned when a binary area is truncated or malformed.
+var ErrBadBinary = errors.New("malformed area binary encoding")
+
+// sortedSlice returns the hexes in the area ordered by R, then Q,
+// so encodings of equal areas are identical.
+func (a *Area) sortedSlice() []hex.Hex {
+	hexes := a.Slice()
+	sort.Slice(hexes, func(i, j int) bool {
+		if hexes[i].R != hexes[j].R {
+			return hexes[i].R < hexes[j].R
+		}
+		return hexes[i].Q < hexes[j].Q
+	})
+	return hexes
+}
+
+// setHexes replaces the contents of the area.
+func (a *Area) setHexes(hexes []hex.Hex) {
+	c := make(mapStore, len(hexes))
+	for _, k := range hexes {
+		c[k] = exists
+	}
+	a.hexes = c
+	a.boundsClean = false
+	a.topology = nil
+	a.pack()
+}
+
+// MarshalJSON encodes the area as a JSON array of hexes.
+//
+// Hexes are sorted, so equal areas encode the same way.
+func (a *Area) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.sortedSlice())
+}
+
+// UnmarshalJSON replaces the contents of the area with
+// a JSON array of hexes.
+func (a *Area) UnmarshalJSON(data []byte) error {
+	var hexes []hex.Hex
+	if err := json.Unmarshal(data, &hexes); err != nil {
+		return err
+	}
+	a.setHexes(hexes)
+	return nil
+}
+
+// MarshalText encodes the area as a list of hexes
+// separated by semicolons, like "0,0;1,-1".
+//
+// Hexes are sorted, so equal areas encode the same way.
+func (a *Area) MarshalText() ([]byte, error) {
+	text := make([]byte, 0)
+	for i, k := range a.sortedSlice() {
+		if i > 0 {
+			text = append(text, ';')
+		}
+		kt, err := k.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		text = append(text, kt...)
+	}
+	return text, nil
+}
+
+// UnmarshalText replaces the contents of the area with
+// hexes made by MarshalText.
+func (a *Area) UnmarshalText(text []byte) error {
+	hexes := make([]hex.Hex, 0)
+	if len(strings.TrimSpace(string(text))) > 0 {
+		for _, part := range strings.Split(string(text), ";") {
+			var k hex.Hex
+			if err := k.UnmarshalText([]byte(part)); err != nil {
+				return err
+			}
+			hexes = append(hexes, k)
+		}
+	}
+	a.setHexes(hexes)
+	return nil
+}
+
+// runRows returns the hexes in the area as rows of runs,
+// ordered by R then Q.
+// Areas that are already stored as runs aren't copied.
+func (a *Area) runRows() []runRow {
+	if s, ok := a.hexes.(*runStore); ok {
+		return s.rows
+	}
+	return runsFrom(a.stored()).rows
+}
+
+// MarshalBinary encodes the area as rows of runs.
+//
+// The layout is a version byte, then the number of rows, then each row.
+// A row is its R (as a delta from the previous row), its number of runs,
+// then the runs. Each run is its starting Q (as a delta from the previous
+// run, or from the first run of the previous row) and its length.
+// All numbers are varints.
+//
+// This is much smaller than the JSON encoding for contiguous areas.
+func (a *Area) MarshalBinary() ([]byte, error) {
+	rows := a.runRows()
+
+	buf := make([]byte, 0, 1+binary.MaxVarintLen64*(1+3*len(rows)))
+	buf = append(buf, binaryVersion)
+	buf = appendUvarint(buf, uint64(len(rows)))
+
+	var lastR, lastRowStart int64
+	for i, row := range rows {
+		if i == 0 {
+			buf = appendVarint(buf, row.r)
+		} else {
+			buf = appendUvarint(buf, uint64(row.r-lastR-1))
+		}
+		lastR = row.r
+
+		runs := row.runs
+		buf = appendUvarint(buf, uint64(len(runs)))
+		buf = appendVarint(buf, runs[0].lo-lastRowStart)
+		buf = appendUvarint(buf, uint64(runs[0].hi-runs[0].lo))
+		lastRowStart = runs[0].lo
+		for j := 1; j < len(runs); j++ {
+			gap := runs[j].lo - runs[j-1].hi - 1
+			buf = appendUvarint(buf, uint64(gap-1))
+			buf = appendUvarint(buf, uint64(runs[j].hi-runs[j].lo))
+		}
+	}
+	return buf, nil
+}
+
+func appendUvarint(buf []byte, v uint64) []byte {
+	var scratch [binary.MaxVarintLen64]byte
+	return append(buf, scratch[:binary.PutUvarint(scratch[:], v)]...)
+}
+
+func appendVarint(buf []byte, v int64) []byte {
+	var scratch [binary.MaxVarintLen64]byte
+	return append(buf, scratch[:binary.PutVarint(scratch[:], v)]...)
+}
+
+// binaryReader pulls varints out of a byte slice.
+type binaryReader struct {
+	buf []byte
+	err error
+}
+
+func (br *binaryReader) uvarint() int64 {
+	if br.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(br.buf)
+	if n <= 0 || v > 1<<62 {
+		br.err = ErrBadBinary
+		return 0
+	}
+	br.buf = br.buf[n:]
+	return int64(v)
+}
+
+func (br *binaryReader) varint() int64 {
+	if br.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(br.buf)
+	if n <= 0 {
+		br.err = ErrBadBinary
+		return 0
+	}
+	br.buf = br.buf[n:]
+	return v
+}
+
+// addChecked returns a+b, and false if that overflows.
+func addChecked(a, b int64) (int64, bool) {
+	sum := a + b
+	if (b > 0 && sum < a) || (b < 0 && sum > a) {
+		return 0, false
+	}
+	return sum, true
+}
+
+// UnmarshalBinary replaces the contents of the area with
+// hexes made by MarshalBinary.
+//
+// Large areas are decoded as runs, so memory use grows with
+// the number of runs in data and not the number of hexes.
+func (a *Area) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return ErrBadBinary
+	}
+	if data[0] != binaryVersion {
+		return fmt.Errorf("%w: %d", ErrUnknownBinaryVersion, data[0])
+	}
+
+	br := &binaryReader{buf: data[1:]}
+	decoded := &runStore{}
+	rowCount := br.uvarint()
+
+	var r, rowStart int64
+	ok := true
+	for i := int64(0); i < rowCount && br.err == nil && ok; i++ {
+		if i == 0 {
+			r = br.varint()
+		} else {
+			r, ok = addChecked(r, br.uvarint()+1)
+		}
+
+		runCount := br.uvarint()
+		if br.err == nil && runCount == 0 {
+			br.err = ErrBadBinary
+		}
+		var q int64
+		for j := int64(0); j < runCount && br.err == nil && ok; j++ {
+			if j == 0 {
+				rowStart, ok = addChecked(rowStart, br.varint())
+				q = rowStart
+			} else {
+				// runs are at least one hex apart, so they can't overlap.
+				q, ok = addChecked(q, br.uvarint()+2)
+			}
+			var hi int64
+			if ok {
+				hi, ok = addChecked(q, br.uvarint())
+			}
+			if ok {
+				ok = hi-q < int64(math.MaxInt-decoded.count)
+			}
+			if ok && br.err == nil {
+				decoded.appendRun(r, interval{lo: q, hi: hi})
+				q = hi
+			}
+		}
+	}
+	if br.err == nil && !ok {
+		br.err = ErrBadBinary
+	}
+	if br.err == nil && len(br.buf) != 0 {
+		br.err = ErrBadBinary
+	}
+	if br.err != nil {
+		return br.err
+	}
+
+	a.hexes = decoded
+	a.boundsClean = false
+	a.topology = nil
+	if decoded.size() < denseMinSize {
+		a.pack()
+	}
+	a.ensureBounds()
+	return nil
+}
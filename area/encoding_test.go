@@ -0,0 +1,166 @@
+package area
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/erinpentecost/hex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAreaJSON(t *testing.T) {
+	for _, a := range []*Area{
+		NewArea(),
+		NewArea(hex.Hex{Q: 3, R: -9}),
+		BigHex(hex.Hex{Q: 1, R: 2}, 4),
+		Polygon(hex.Hex{Q: -5, R: 0}, hex.Hex{Q: 6, R: -3}, hex.Hex{Q: 2, R: 7}),
+	} {
+		b, err := json.Marshal(a)
+		require.NoError(t, err)
+
+		back := NewArea(hex.Origin())
+		require.NoError(t, json.Unmarshal(b, back))
+		assert.ElementsMatch(t, a.Slice(), back.Slice())
+		assert.Equal(t, a.String(), back.String())
+	}
+
+	a := NewArea(hex.Hex{Q: 1, R: 0}, hex.Hex{Q: 0, R: 0}, hex.Hex{Q: -1, R: 1})
+	assert.Equal(t, `[{"Q":0,"R":0},{"Q":1,"R":0},{"Q":-1,"R":1}]`, a.String())
+
+	// logo.json style input.
+	var logo Area
+	require.NoError(t, json.Unmarshal([]byte(`[{"Q":2,"R":-2},{"Q":1,"R":-1}]`), &logo))
+	assert.True(t, NewArea(hex.Hex{Q: 2, R: -2}, hex.Hex{Q: 1, R: -1}).Equals(&logo))
+}
+
+func TestAreaText(t *testing.T) {
+	a := NewArea(hex.Hex{Q: 1, R: 0}, hex.Hex{Q: 0, R: 0}, hex.Hex{Q: -1, R: 1})
+	text, err := a.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "0,0;1,0;-1,1", string(text))
+
+	back := NewArea()
+	require.NoError(t, back.UnmarshalText(text))
+	assert.True(t, a.Equals(back))
+
+	empty := NewArea()
+	text, err = empty.MarshalText()
+	require.NoError(t, err)
+	require.NoError(t, back.UnmarshalText(text))
+	assert.Equal(t, 0, back.Size())
+
+	assert.ErrorIs(t, back.UnmarshalText([]byte("0,0;1")), hex.ErrBadHexText)
+}
+
+func TestAreaBinary(t *testing.T) {
+	for name, a := range map[string]*Area{
+		"empty":   NewArea(),
+		"single":  NewArea(hex.Hex{Q: -300, R: 7000}),
+		"bighex":  BigHex(hex.Hex{Q: 1, R: 2}, 40),
+		"circle":  Circle(hex.Hex{Q: -20, R: 9}, 30),
+		"polygon": Polygon(hex.Hex{Q: -15, R: 0}, hex.Hex{Q: 16, R: -13}, hex.Hex{Q: 2, R: 27}, hex.Hex{Q: -3, R: 5}),
+		"gaps":    NewArea(Line(hex.Hex{Q: -9, R: -9}, hex.Hex{Q: 9, R: 9}).Union(Ring(hex.Origin(), 5)).Build().Slice()...),
+	} {
+		t.Run(name, func(t *testing.T) {
+			b, err := a.MarshalBinary()
+			require.NoError(t, err)
+
+			back := NewArea(hex.Origin())
+			require.NoError(t, back.UnmarshalBinary(b))
+			assert.ElementsMatch(t, a.Slice(), back.Slice())
+
+			again, err := back.MarshalBinary()
+			require.NoError(t, err)
+			assert.Equal(t, b, again)
+
+			if a.Size() > 100 {
+				js, err := a.MarshalJSON()
+				require.NoError(t, err)
+				assert.Less(t, len(b)*10, len(js))
+			}
+		})
+	}
+}
+
+func TestAreaBinaryErrors(t *testing.T) {
+	b, err := BigHex(hex.Origin(), 3).MarshalBinary()
+	require.NoError(t, err)
+
+	a := NewArea()
+	assert.ErrorIs(t, a.UnmarshalBinary(nil), ErrBadBinary)
+	assert.ErrorIs(t, a.UnmarshalBinary(b[:len(b)-1]), ErrBadBinary)
+	assert.ErrorIs(t, a.UnmarshalBinary(append(b, 0)), ErrBadBinary)
+
+	b[0] = 99
+	assert.ErrorIs(t, a.UnmarshalBinary(b), ErrUnknownBinaryVersion)
+}
+
+func TestAreaBinaryHostile(t *testing.T) {
+	// header starts a binary area with some number of rows.
+	header := func(rows uint64) []byte {
+		return appendUvarint([]byte{binaryVersion}, rows)
+	}
+
+	// a tiny payload for a huge run decodes without visiting every hex.
+	b := header(1)
+	b = appendVarint(b, 0)
+	b = appendUvarint(b, 1)
+	b = appendVarint(b, 0)
+	b = appendUvarint(b, 1<<62)
+	var a Area
+	require.NoError(t, a.UnmarshalBinary(b))
+	assert.Equal(t, 1<<62+1, a.Size())
+	assert.True(t, a.ContainsHexes(hex.Hex{Q: 1 << 61}))
+	again, err := a.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, b, again)
+
+	// the end of a run overflows.
+	b = header(1)
+	b = appendVarint(b, 0)
+	b = appendUvarint(b, 1)
+	b = appendVarint(b, math.MaxInt64-5)
+	b = appendUvarint(b, 100)
+	assert.ErrorIs(t, a.UnmarshalBinary(b), ErrBadBinary)
+
+	// the start of a later run overflows.
+	b = header(1)
+	b = appendVarint(b, 0)
+	b = appendUvarint(b, 2)
+	b = appendVarint(b, math.MaxInt64-5)
+	b = appendUvarint(b, 0)
+	b = appendUvarint(b, 10)
+	b = appendUvarint(b, 0)
+	assert.ErrorIs(t, a.UnmarshalBinary(b), ErrBadBinary)
+
+	// R overflows.
+	b = header(2)
+	b = appendVarint(b, math.MaxInt64)
+	b = appendUvarint(b, 1)
+	b = appendVarint(b, 0)
+	b = appendUvarint(b, 0)
+	b = appendUvarint(b, 0)
+	b = appendUvarint(b, 1)
+	b = appendVarint(b, 0)
+	b = appendUvarint(b, 0)
+	assert.ErrorIs(t, a.UnmarshalBinary(b), ErrBadBinary)
+
+	// the total number of hexes overflows.
+	b = header(2)
+	for i := 0; i < 2; i++ {
+		if i == 0 {
+			b = appendVarint(b, 0)
+		} else {
+			b = appendUvarint(b, 0)
+		}
+		b = appendUvarint(b, 1)
+		b = appendVarint(b, 0)
+		b = appendUvarint(b, 1<<62)
+	}
+	assert.ErrorIs(t, a.UnmarshalBinary(b), ErrBadBinary)
+
+	// a huge row count with nothing behind it.
+	assert.ErrorIs(t, a.UnmarshalBinary(header(1<<60)), ErrBadBinary)
+}
@@ -0,0 +1,187 @@
+package area
+
+import (
+	"encoding/json"
+
+	"github.com/erinpentecost/hex"
+)
+
+// HexMap stores a value for each hex in a set of hexes,
+// like terrain or elevation.
+//
+// The zero HexMap is empty and ready to use.
+type HexMap[T any] struct {
+	values map[hex.Hex]T
+	// keys is an Area that reads straight from values,
+	// so each hex is only stored once.
+	keys *Area
+}
+
+// NewHexMap creates an empty HexMap.
+func NewHexMap[T any]() *HexMap[T] {
+	m := &HexMap[T]{}
+	m.init()
+	return m
+}
+
+// init makes the zero HexMap usable.
+func (m *HexMap[T]) init() {
+	if m.values == nil {
+		m.values = make(map[hex.Hex]T)
+		m.keys = &Area{hexes: keyStore[T](m.values)}
+	}
+}
+
+// Get returns the value stored for h, and whether there was one.
+func (m *HexMap[T]) Get(h hex.Hex) (T, bool) {
+	v, ok := m.values[h]
+	return v, ok
+}
+
+// Set stores a value for h.
+func (m *HexMap[T]) Set(h hex.Hex, value T) {
+	m.init()
+	if _, ok := m.values[h]; !ok {
+		m.keys.growBounds(h)
+	}
+	m.values[h] = value
+}
+
+// Fill stores the same value for every hex in the area built by b.
+func (m *HexMap[T]) Fill(b Builder, value T) {
+	b.Build().Each(func(h hex.Hex) bool {
+		m.Set(h, value)
+		return true
+	})
+}
+
+// Delete removes the values stored for hexes.
+func (m *HexMap[T]) Delete(hexes ...hex.Hex) {
+	m.init()
+	for _, h := range hexes {
+		if _, ok := m.values[h]; ok {
+			delete(m.values, h)
+			m.keys.shrinkBounds(h)
+		}
+	}
+}
+
+// Len returns the number of hexes with values.
+func (m *HexMap[T]) Len() int {
+	return len(m.values)
+}
+
+// Keys returns the area of hexes that have values.
+//
+// This is a view, not a copy: it changes along with the map,
+// and panics if it's changed directly. Use Clone to get an area
+// that can be changed.
+func (m *HexMap[T]) Keys() *Area {
+	m.init()
+	return m.keys
+}
+
+// Each calls fn with every hex and its value.
+// It stops early if fn returns false.
+// The order hexes are visited in is not set.
+//
+// fn must not modify the map.
+func (m *HexMap[T]) Each(fn func(hex.Hex, T) bool) {
+	for h, v := range m.values {
+		if !fn(h, v) {
+			return
+		}
+	}
+}
+
+// Filter returns the area of hexes whose values match a predicate.
+func (m *HexMap[T]) Filter(keep func(hex.Hex, T) bool) *Area {
+	a := NewArea()
+	for h, v := range m.values {
+		if keep(h, v) {
+			a.Add(h)
+		}
+	}
+	return a
+}
+
+// Mask returns a new HexMap with only the hexes that are
+// also in the area built by b.
+//
+// Combine with Filter to find hexes inside a shape:
+//
+//	forest := terrain.Mask(area.Polygon(p...)).Filter(isForest)
+func (m *HexMap[T]) Mask(b Builder) *HexMap[T] {
+	masked := NewHexMap[T]()
+	b.Build().Each(func(h hex.Hex) bool {
+		if v, ok := m.values[h]; ok {
+			masked.Set(h, v)
+		}
+		return true
+	})
+	return masked
+}
+
+// MarshalJSON encodes the map as a JSON object keyed by hex text.
+func (m *HexMap[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.values)
+}
+
+// UnmarshalJSON replaces the contents of the map with
+// a JSON object made by MarshalJSON.
+func (m *HexMap[T]) UnmarshalJSON(data []byte) error {
+	values := make(map[hex.Hex]T)
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	// refill the same map, since Keys reads from it.
+	m.init()
+	for h := range m.values {
+		delete(m.values, h)
+	}
+	for h, v := range values {
+		m.values[h] = v
+	}
+	m.keys.boundsClean = false
+	return nil
+}
+
+// keyStore is the store behind HexMap.Keys.
+// It can't be changed through the Area, only through the HexMap.
+type keyStore[T any] map[hex.Hex]T
+
+func (k keyStore[T]) has(h hex.Hex) bool {
+	_, ok := k[h]
+	return ok
+}
+
+func (k keyStore[T]) add(h hex.Hex) {
+	panic("the keys of a HexMap can't be changed directly")
+}
+
+func (k keyStore[T]) remove(h hex.Hex) {
+	panic("the keys of a HexMap can't be changed directly")
+}
+
+func (k keyStore[T]) size() int {
+	return len(k)
+}
+
+func (k keyStore[T]) each(fn func(hex.Hex) bool) bool {
+	for h := range k {
+		if !fn(h) {
+			return false
+		}
+	}
+	return true
+}
+
+// clone copies the keys into a plain map, so the copy
+// can be changed.
+func (k keyStore[T]) clone() store {
+	c := make(mapStore, len(k))
+	for h := range k {
+		c[h] = exists
+	}
+	return c
+}
@@ -0,0 +1,160 @@
+package area
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/erinpentecost/hex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type terrain byte
+
+const (
+	grass terrain = iota
+	forest
+	water
+)
+
+func TestHexMap(t *testing.T) {
+	m := NewHexMap[terrain]()
+	_, ok := m.Get(hex.Origin())
+	assert.False(t, ok)
+
+	m.Set(hex.Origin(), forest)
+	m.Set(hex.Hex{Q: 1, R: 0}, water)
+	v, ok := m.Get(hex.Origin())
+	assert.True(t, ok)
+	assert.Equal(t, forest, v)
+	assert.Equal(t, 2, m.Len())
+
+	m.Set(hex.Origin(), grass)
+	v, _ = m.Get(hex.Origin())
+	assert.Equal(t, grass, v)
+	assert.Equal(t, 2, m.Len())
+
+	m.Delete(hex.Origin(), hex.Hex{Q: 9, R: 9})
+	_, ok = m.Get(hex.Origin())
+	assert.False(t, ok)
+	assert.Equal(t, 1, m.Len())
+	assert.ElementsMatch(t, []hex.Hex{{Q: 1, R: 0}}, m.Keys().Slice())
+}
+
+func TestHexMapKeysIsAView(t *testing.T) {
+	m := NewHexMap[int]()
+	keys := m.Keys()
+	m.Fill(BigHex(hex.Origin(), 2), 7)
+	assert.True(t, BigHex(hex.Origin(), 2).Equals(keys))
+
+	m.Delete(hex.Hex{Q: 2, R: 0})
+	assert.Equal(t, 18, keys.Size())
+	requireSameBounds(t, keys)
+}
+
+func TestHexMapFilterAndMask(t *testing.T) {
+	m := NewHexMap[terrain]()
+	m.Fill(BigHex(hex.Origin(), 10), grass)
+	m.Fill(Circle(hex.Hex{Q: 3, R: 3}, 4), forest)
+	m.Fill(Line(hex.Hex{Q: -10, R: 0}, hex.Hex{Q: 10, R: 0}), water)
+
+	isForest := func(h hex.Hex, v terrain) bool {
+		return v == forest
+	}
+	allForest := m.Filter(isForest)
+	expected := Circle(hex.Hex{Q: 3, R: 3}, 4).Subtract(Line(hex.Hex{Q: -10, R: 0}, hex.Hex{Q: 10, R: 0})).Build()
+	assert.True(t, expected.Equals(allForest))
+
+	// select all forest hexes inside a polygon.
+	polygon := Polygon(hex.Hex{Q: 0, R: 0}, hex.Hex{Q: 8, R: 0}, hex.Hex{Q: 0, R: 8})
+	masked := m.Mask(polygon)
+	assert.True(t, polygon.Intersection(m.Keys()).Build().Equals(masked.Keys()))
+	forestInPolygon := masked.Filter(isForest)
+	assert.True(t, expected.Intersection(polygon).Build().Equals(forestInPolygon))
+
+	// masking doesn't change the original.
+	assert.Equal(t, BigHex(hex.Origin(), 10).Size(), m.Len())
+}
+
+func TestHexMapJSON(t *testing.T) {
+	m := NewHexMap[string]()
+	m.Set(hex.Hex{Q: -1, R: 2}, "a")
+	m.Set(hex.Hex{Q: 3, R: 0}, "b")
+
+	b, err := json.Marshal(m)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"-1,2":"a","3,0":"b"}`, string(b))
+
+	back := NewHexMap[string]()
+	require.NoError(t, json.Unmarshal(b, back))
+	assert.Equal(t, 2, back.Len())
+	v, _ := back.Get(hex.Hex{Q: 3, R: 0})
+	assert.Equal(t, "b", v)
+	assert.True(t, m.Keys().Equals(back.Keys()))
+}
+
+func TestHexMapZero(t *testing.T) {
+	var m HexMap[terrain]
+	_, ok := m.Get(hex.Origin())
+	assert.False(t, ok)
+	assert.Equal(t, 0, m.Keys().Size())
+
+	m.Set(hex.Origin(), forest)
+	m.Fill(BigHex(hex.Hex{Q: 5}, 1), water)
+	assert.Equal(t, 8, m.Len())
+	assert.Equal(t, 8, m.Keys().Size())
+
+	var d HexMap[terrain]
+	d.Delete(hex.Origin())
+	assert.Equal(t, 0, d.Len())
+}
+
+func TestHexMapUnmarshalKeepsKeys(t *testing.T) {
+	m := NewHexMap[terrain]()
+	m.Set(hex.Origin(), grass)
+	keys := m.Keys()
+
+	src := NewHexMap[terrain]()
+	src.Fill(BigHex(hex.Hex{Q: 3, R: 3}, 1), forest)
+	b, err := json.Marshal(src)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(b, m))
+
+	assert.True(t, keys == m.Keys())
+	assert.True(t, src.Keys().Equals(keys))
+}
+
+func TestHexMapKeysShareValues(t *testing.T) {
+	m := NewHexMap[terrain]()
+	m.Fill(BigHex(hex.Hex{Q: 4, R: -1}, 3), water)
+	keys := m.Keys()
+
+	// the keys read from the values map instead of keeping a copy.
+	_, ok := keys.hexes.(keyStore[terrain])
+	require.True(t, ok)
+	assert.True(t, BigHex(hex.Hex{Q: 4, R: -1}, 3).Equals(keys))
+	requireSameBounds(t, keys)
+
+	m.Set(hex.Hex{Q: 20, R: 20}, grass)
+	requireSameBounds(t, keys)
+	m.Delete(hex.Hex{Q: 20, R: 20}, hex.Hex{Q: 7, R: -1})
+	requireSameBounds(t, keys)
+	visited := 0
+	keys.Each(func(h hex.Hex) bool {
+		_, ok := m.Get(h)
+		require.True(t, ok)
+		visited++
+		return true
+	})
+	assert.Equal(t, m.Len(), visited)
+
+	// clones can be changed, but the view can't.
+	c := keys.Clone()
+	c.Add(hex.Hex{Q: 50})
+	assert.Equal(t, m.Len()+1, c.Size())
+	assert.Panics(t, func() { keys.Add(hex.Hex{Q: 50}) })
+
+	require.NoError(t, json.Unmarshal([]byte(`{"-9,1":0,"3,3":1}`), m))
+	assert.ElementsMatch(t, []hex.Hex{{Q: -9, R: 1}, {Q: 3, R: 3}}, keys.Slice())
+	requireSameBounds(t, keys)
+}
@@ -0,0 +1,69 @@
+package area
+
+import "github.com/erinpentecost/hex"
+
+// Compact replaces every complete set of seven children in the area
+// with their parent, up to the given number of levels.
+//
+// The result has one area per level. The area at index i holds
+// hexes that are i levels up from the original grid, and
+// no two hexes in the result cover the same original hex.
+// The last area holds the parents that could not be compacted further.
+//
+// Levels below zero are treated as zero, which returns a copy of a.
+//
+// See hex.Hex.Parent for how levels relate to each other.
+func (a *Area) Compact(levels int) []*Area {
+	if levels < 0 {
+		levels = 0
+	}
+	compacted := make([]*Area, 0, levels+1)
+	cur := a.stored()
+	for level := 0; level < levels; level++ {
+		// find the parents that have all their children.
+		parents := make(mapStore)
+		cur.each(func(k hex.Hex) bool {
+			p := k.Parent()
+			if parents.has(p) {
+				return true
+			}
+			children := p.Children()
+			for _, c := range children {
+				if !cur.has(c) {
+					return true
+				}
+			}
+			parents[p] = exists
+			return true
+		})
+
+		remaining := make(mapStore)
+		cur.each(func(k hex.Hex) bool {
+			if !parents.has(k.Parent()) {
+				remaining[k] = exists
+			}
+			return true
+		})
+		compacted = append(compacted, (&Area{hexes: remaining}).pack())
+		cur = parents
+	}
+
+	return append(compacted, (&Area{hexes: cur.clone()}).pack())
+}
+
+// Uncompact is the inverse of Compact. It expands every area
+// back down to the original grid and joins them together.
+func Uncompact(levels []*Area) *Area {
+	c := make(mapStore)
+	for level, a := range levels {
+		a.Each(func(k hex.Hex) bool {
+			for _, d := range k.Descendants(level) {
+				c[d] = exists
+			}
+			return true
+		})
+	}
+	return (&Area{
+		hexes: c,
+	}).pack()
+}
@@ -0,0 +1,63 @@
+package area
+
+import (
+	"math"
+	"testing"
+
+	"github.com/erinpentecost/hex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompact(t *testing.T) {
+	for _, a := range []*Area{
+		NewArea(),
+		BigHex(hex.Origin(), 12),
+		Circle(hex.Hex{Q: 5, R: -9}, 15),
+		Polygon(hex.Hex{Q: -15, R: 0}, hex.Hex{Q: 16, R: -13}, hex.Hex{Q: 2, R: 27}),
+	} {
+		levels := a.Compact(3)
+		require.Len(t, levels, 4)
+
+		total := 0
+		for level, l := range levels {
+			total += l.Size() * int(math.Pow(7, float64(level)))
+		}
+		assert.Equal(t, a.Size(), total, "compacted hexes overlap")
+		assert.ElementsMatch(t, a.Slice(), Uncompact(levels).Slice())
+	}
+
+	// a single parent compacts to one hex.
+	p := hex.Hex{Q: 2, R: -1}
+	children := p.Children()
+	levels := NewArea(children[:]...).Compact(2)
+	assert.Equal(t, 0, levels[0].Size())
+	assert.ElementsMatch(t, []hex.Hex{p}, levels[1].Slice())
+	assert.Equal(t, 0, levels[2].Size())
+
+	// big areas compact to far fewer hexes.
+	big := BigHex(hex.Origin(), 40)
+	count := 0
+	for _, l := range big.Compact(3) {
+		count += l.Size()
+	}
+	assert.Less(t, count*3, big.Size())
+
+	// no levels just copies the area.
+	for _, levels := range []int{0, -1} {
+		same := big.Compact(levels)
+		require.Len(t, same, 1)
+		assert.True(t, big.Equals(same[0]))
+	}
+}
+
+func TestCompactFarAway(t *testing.T) {
+	// a complete set of children way out from the origin.
+	p := hex.Hex{Q: 1 << 58, R: -(1 << 57) + 3}
+	children := p.Children()
+	a := NewArea(children[:]...).Union(NewArea(hex.Hex{Q: -(1 << 60), R: 5})).Build()
+	levels := a.Compact(2)
+	require.Len(t, levels, 3)
+	assert.ElementsMatch(t, []hex.Hex{p}, levels[1].Slice())
+	assert.True(t, a.Equals(Uncompact(levels)))
+}
@@ -24,7 +24,10 @@ func minInt(a, k int64) int64 {
 // The order of elements returned is not set.
 // A radius of 0 will return the center hex.
 func BigHex(center hex.Hex, radius int64) *Area {
-	area := NewArea()
+	area := &Area{
+		hexes: newStore(int(3*radius*(radius+1)+1),
+			center.R-radius, center.R+radius, center.Q-radius, center.Q+radius),
+	}
 	bf := boundsFinder{}
 	for q := -1 * radius; q <= radius; q++ {
 		r1 := maxInt(-1*radius, -1*(q+radius))
@@ -35,12 +38,36 @@ func BigHex(center hex.Hex, radius int64) *Area {
 				Q: q + center.Q,
 				R: r + center.R,
 			}
-			area.hexes[h] = exists
+			area.hexes.add(h)
 			bf.visit(&h)
 		}
 	}
 
-	return bf.applyTo(area)
+	return bf.applyTo(area).pack()
+}
+
+// Ring returns the set of hexes that are exactly radius away from center.
+// A radius of 0 will return the center hex.
+func Ring(center hex.Hex, radius int64) *Area {
+	area := NewArea()
+	bf := boundsFinder{}
+	for _, h := range hex.Ring(center, radius) {
+		area.hexes.add(h)
+		bf.visit(&h)
+	}
+	return bf.applyTo(area).pack()
+}
+
+// Spiral returns the set of hexes within radius of center.
+// This is the same set of hexes as BigHex, but built ring by ring.
+func Spiral(center hex.Hex, radius int64) *Area {
+	area := NewArea()
+	bf := boundsFinder{}
+	for _, h := range hex.Spiral(center, radius) {
+		area.hexes.add(h)
+		bf.visit(&h)
+	}
+	return bf.applyTo(area).pack()
 }
 
 // Circle draws a circle. At small radiuses, this is just like BigHex.
@@ -62,7 +89,7 @@ func Circle(center hex.Hex, radius int64) *Area {
 			x, y := hex.HexFractional{Q: float64(q), R: float64(r)}.ToCartesian()
 			dist := x*x + y*y
 			if dist <= rs || internal.CloseEnough(dist, rs) {
-				area.hexes[hex.Hex{Q: q, R: r}] = exists
+				area.hexes.add(hex.Hex{Q: q, R: r})
 			}
 		}
 	}
@@ -73,41 +100,92 @@ func Circle(center hex.Hex, radius int64) *Area {
 
 // Rectangle returns the set of hexes that form a rectangular
 // area that's a bounding box of all the supplied points.
+//
+// The rectangle is laid out in odd-r offset coordinates.
+// Use OffsetRectangle to pick another layout.
 func Rectangle(p ...hex.Hex) *Area {
+	return OffsetRectangle(hex.OddR, p...)
+}
+
+// OffsetRectangle returns the set of hexes that form a rectangular
+// area in the offset grid with the given parity. The rectangle
+// is the bounding box of all the supplied points in that grid.
+func OffsetRectangle(parity hex.OffsetParity, p ...hex.Hex) *Area {
 	if len(p) == 0 {
 		return NewArea()
 	}
 
-	area := NewArea(p...)
+	first := p[0].ToOffset(parity)
+	minCol, maxCol, minRow, maxRow := first.Col, first.Col, first.Row, first.Row
+	for _, h := range p[1:] {
+		o := h.ToOffset(parity)
+		minCol = minInt(minCol, o.Col)
+		maxCol = maxInt(maxCol, o.Col)
+		minRow = minInt(minRow, o.Row)
+		maxRow = maxInt(maxRow, o.Row)
+	}
 
-	for r := area.minR; r <= area.maxR; r++ {
-		rOffset := r / 2
-		for q := area.minQ - rOffset; q <= area.maxQ-rOffset; q++ {
-			area.hexes[hex.Hex{
-				Q: q,
-				R: r,
-			}] = exists
+	area := NewArea()
+	bf := boundsFinder{}
+	for row := minRow; row <= maxRow; row++ {
+		for col := minCol; col <= maxCol; col++ {
+			h := hex.OffsetCoord{Col: col, Row: row}.ToHex(parity)
+			area.hexes.add(h)
+			bf.visit(&h)
 		}
 	}
-	return area
+	return bf.applyTo(area).pack()
+}
+
+// LineStyle picks how hexes are chosen for a line.
+type LineStyle byte
+
+const (
+	// LerpLine picks hexes by linear interpolation, like hex.LineTo.
+	// Lines that run along hex edges may pick either side.
+	LerpLine LineStyle = iota
+	// NudgedLine picks hexes like hex.NudgedLineTo.
+	// Lines are symmetric: reversing the points gives the same hexes.
+	NudgedLine
+	// SupercoverLine picks every hex the line touches,
+	// like hex.SupercoverLineTo.
+	SupercoverLine
+)
+
+func (s LineStyle) trace(a, b hex.Hex) []hex.Hex {
+	switch s {
+	case LerpLine:
+		return a.LineTo(b)
+	case NudgedLine:
+		return a.NudgedLineTo(b)
+	case SupercoverLine:
+		return a.SupercoverLineTo(b)
+	}
+	panic("unknown line style")
 }
 
 // Line traces line segments along the provided points.
 func Line(p ...hex.Hex) *Area {
+	return StyledLine(LerpLine, p...)
+}
+
+// StyledLine traces line segments along the provided points,
+// using style to pick hexes.
+func StyledLine(style LineStyle, p ...hex.Hex) *Area {
 	switch len(p) {
 	case 0:
 		return NewArea()
 	case 1:
 		return NewArea(p[0])
 	case 2:
-		NewArea(p[0].LineTo(p[1])...)
+		return NewArea(style.trace(p[0], p[1])...)
 	}
 
 	// get outline
 	outline := NewBuilder(p...)
 	last := p[0]
 	for _, point := range p[1:] {
-		outline = outline.Union(NewArea(last.LineTo(point)...))
+		outline = outline.Union(NewArea(style.trace(last, point)...))
 		last = point
 	}
 
@@ -150,15 +228,15 @@ func fill(edges []*Area, vertices []hex.Hex) *Area {
 			testHex := hex.Hex{Q: q, R: r}
 
 			for _, outline := range edges {
-				if _, hit := outline.hexes[testHex]; hit {
+				if outline.stored().has(testHex) {
 					inside = !inside
 					// always include the intersection hex
-					f.hexes[testHex] = exists
+					f.hexes.add(testHex)
 				}
 			}
 
 			if inside {
-				f.hexes[testHex] = exists
+				f.hexes.add(testHex)
 			}
 		}
 	}
@@ -0,0 +1,381 @@
+package area
+
+import (
+	"sort"
+
+	"github.com/erinpentecost/hex"
+)
+
+// interval is a run of hexes in a row, from Q=lo to Q=hi inclusive.
+type interval struct {
+	lo, hi int64
+}
+
+func (i interval) length() int {
+	return int(i.hi - i.lo + 1)
+}
+
+// runRow is the sorted, non-touching intervals in row r.
+type runRow struct {
+	r    int64
+	runs []interval
+}
+
+// runStore keeps each row of the area as a sorted list of intervals.
+// Operations on two runStores cost about as much as the number of runs,
+// no matter how many hexes there are.
+type runStore struct {
+	// rows is sorted by R and has no empty rows.
+	rows  []runRow
+	count int
+}
+
+// findRow returns the index of row r, or where it should be inserted.
+func (s *runStore) findRow(r int64) (int, bool) {
+	i := sort.Search(len(s.rows), func(i int) bool { return s.rows[i].r >= r })
+	return i, i < len(s.rows) && s.rows[i].r == r
+}
+
+// findRun returns the index of the first run that ends at or after q.
+func findRun(runs []interval, q int64) int {
+	return sort.Search(len(runs), func(i int) bool { return runs[i].hi >= q })
+}
+
+func (s *runStore) has(h hex.Hex) bool {
+	ri, ok := s.findRow(h.R)
+	if !ok {
+		return false
+	}
+	runs := s.rows[ri].runs
+	i := findRun(runs, h.Q)
+	return i < len(runs) && runs[i].lo <= h.Q
+}
+
+func (s *runStore) add(h hex.Hex) {
+	ri, ok := s.findRow(h.R)
+	if !ok {
+		s.rows = append(s.rows, runRow{})
+		copy(s.rows[ri+1:], s.rows[ri:])
+		s.rows[ri] = runRow{r: h.R}
+	}
+	runs := s.rows[ri].runs
+	q := h.Q
+	i := findRun(runs, q)
+	if i < len(runs) && runs[i].lo <= q {
+		return
+	}
+
+	left := i > 0 && runs[i-1].hi == q-1
+	right := i < len(runs) && runs[i].lo == q+1
+	switch {
+	case left && right:
+		runs[i-1].hi = runs[i].hi
+		runs = append(runs[:i], runs[i+1:]...)
+	case left:
+		runs[i-1].hi = q
+	case right:
+		runs[i].lo = q
+	default:
+		runs = append(runs, interval{})
+		copy(runs[i+1:], runs[i:])
+		runs[i] = interval{lo: q, hi: q}
+	}
+	s.rows[ri].runs = runs
+	s.count++
+}
+
+func (s *runStore) remove(h hex.Hex) {
+	ri, ok := s.findRow(h.R)
+	if !ok {
+		return
+	}
+	runs := s.rows[ri].runs
+	q := h.Q
+	i := findRun(runs, q)
+	if i >= len(runs) || runs[i].lo > q {
+		return
+	}
+
+	switch run := runs[i]; {
+	case run.lo == run.hi:
+		runs = append(runs[:i], runs[i+1:]...)
+	case run.lo == q:
+		runs[i].lo++
+	case run.hi == q:
+		runs[i].hi--
+	default:
+		runs = append(runs, interval{})
+		copy(runs[i+1:], runs[i:])
+		runs[i].hi = q - 1
+		runs[i+1].lo = q + 1
+	}
+	s.count--
+
+	if len(runs) == 0 {
+		s.rows = append(s.rows[:ri], s.rows[ri+1:]...)
+		return
+	}
+	s.rows[ri].runs = runs
+}
+
+func (s *runStore) size() int {
+	return s.count
+}
+
+func (s *runStore) each(fn func(hex.Hex) bool) bool {
+	for _, row := range s.rows {
+		for _, run := range row.runs {
+			for q := run.lo; q <= run.hi; q++ {
+				if !fn(hex.Hex{Q: q, R: row.r}) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+func (s *runStore) clone() store {
+	c := &runStore{
+		rows:  make([]runRow, len(s.rows)),
+		count: s.count,
+	}
+	for i, row := range s.rows {
+		c.rows[i] = runRow{r: row.r, runs: append([]interval(nil), row.runs...)}
+	}
+	return c
+}
+
+func (s *runStore) bounds() (minR, maxR, minQ, maxQ int64, ok bool) {
+	if len(s.rows) == 0 {
+		return 0, 0, 0, 0, false
+	}
+	minR, maxR = s.rows[0].r, s.rows[len(s.rows)-1].r
+	minQ, maxQ = s.rows[0].runs[0].lo, s.rows[0].runs[0].hi
+	for _, row := range s.rows {
+		minQ = minInt(minQ, row.runs[0].lo)
+		maxQ = maxInt(maxQ, row.runs[len(row.runs)-1].hi)
+	}
+	return minR, maxR, minQ, maxQ, true
+}
+
+// numRuns returns the number of intervals in the store.
+func (s *runStore) numRuns() int {
+	n := 0
+	for _, row := range s.rows {
+		n += len(row.runs)
+	}
+	return n
+}
+
+// translate moves every run by offset.
+func (s *runStore) translate(offset hex.Hex) *runStore {
+	c := &runStore{
+		rows:  make([]runRow, len(s.rows)),
+		count: s.count,
+	}
+	for i, row := range s.rows {
+		runs := make([]interval, len(row.runs))
+		for j, run := range row.runs {
+			runs[j] = interval{lo: run.lo + offset.Q, hi: run.hi + offset.Q}
+		}
+		c.rows[i] = runRow{r: row.r + offset.R, runs: runs}
+	}
+	return c
+}
+
+// appendSorted adds h, which must come after every hex
+// already in the store when ordered by R, then Q.
+func (s *runStore) appendSorted(h hex.Hex) {
+	s.appendRun(h.R, interval{lo: h.Q, hi: h.Q})
+}
+
+// appendRun adds a run to row r. Like appendSorted, the run must
+// come after every hex already in the store, but it may touch
+// or overlap the last run.
+func (s *runStore) appendRun(r int64, run interval) {
+	if n := len(s.rows); n == 0 || s.rows[n-1].r != r {
+		s.rows = append(s.rows, runRow{r: r})
+	}
+	row := &s.rows[len(s.rows)-1]
+	if n := len(row.runs); n > 0 && row.runs[n-1].hi+1 >= run.lo {
+		if last := &row.runs[n-1]; last.hi < run.hi {
+			s.count += int(run.hi - last.hi)
+			last.hi = run.hi
+		}
+		return
+	}
+	row.runs = append(row.runs, run)
+	s.count += run.length()
+}
+
+// runsFrom copies any store into a runStore.
+func runsFrom(src store) *runStore {
+	s := &runStore{}
+	switch src := src.(type) {
+	case *runStore:
+		return src.clone().(*runStore)
+	case *bitStore:
+		// bitStores are already visited in order.
+		src.each(func(h hex.Hex) bool {
+			s.appendSorted(h)
+			return true
+		})
+	default:
+		hexes := make([]hex.Hex, 0, src.size())
+		src.each(func(h hex.Hex) bool {
+			hexes = append(hexes, h)
+			return true
+		})
+		sort.Slice(hexes, func(i, j int) bool {
+			if hexes[i].R != hexes[j].R {
+				return hexes[i].R < hexes[j].R
+			}
+			return hexes[i].Q < hexes[j].Q
+		})
+		for _, h := range hexes {
+			s.appendSorted(h)
+		}
+	}
+	return s
+}
+
+// countRuns returns the number of intervals needed to hold src.
+func countRuns(src store) int {
+	switch s := src.(type) {
+	case *runStore:
+		return s.numRuns()
+	case *bitStore:
+		return s.numRuns()
+	}
+	n := 0
+	src.each(func(h hex.Hex) bool {
+		if !src.has(hex.Hex{Q: h.Q - 1, R: h.R}) {
+			n++
+		}
+		return true
+	})
+	return n
+}
+
+// mergeRows combines the rows of a and b, one row at a time.
+// fn gets nil for a row that is missing from one side,
+// and returns the runs for that row in the result.
+func mergeRows(a, b *runStore, fn func(x, y []interval) []interval) *runStore {
+	out := &runStore{}
+	emit := func(r int64, runs []interval) {
+		if len(runs) == 0 {
+			return
+		}
+		out.rows = append(out.rows, runRow{r: r, runs: runs})
+		for _, run := range runs {
+			out.count += run.length()
+		}
+	}
+	i, j := 0, 0
+	for i < len(a.rows) || j < len(b.rows) {
+		switch {
+		case j == len(b.rows) || (i < len(a.rows) && a.rows[i].r < b.rows[j].r):
+			emit(a.rows[i].r, fn(a.rows[i].runs, nil))
+			i++
+		case i == len(a.rows) || b.rows[j].r < a.rows[i].r:
+			emit(b.rows[j].r, fn(nil, b.rows[j].runs))
+			j++
+		default:
+			emit(a.rows[i].r, fn(a.rows[i].runs, b.rows[j].runs))
+			i++
+			j++
+		}
+	}
+	return out
+}
+
+// unionRuns returns the runs covering x or y.
+func unionRuns(x, y []interval) []interval {
+	out := make([]interval, 0, len(x)+len(y))
+	push := func(run interval) {
+		if n := len(out); n > 0 && out[n-1].hi+1 >= run.lo {
+			out[n-1].hi = maxInt(out[n-1].hi, run.hi)
+			return
+		}
+		out = append(out, run)
+	}
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		if j == len(y) || (i < len(x) && x[i].lo < y[j].lo) {
+			push(x[i])
+			i++
+		} else {
+			push(y[j])
+			j++
+		}
+	}
+	return out
+}
+
+// intersectRuns returns the runs covering both x and y.
+func intersectRuns(x, y []interval) []interval {
+	var out []interval
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		lo, hi := maxInt(x[i].lo, y[j].lo), minInt(x[i].hi, y[j].hi)
+		if lo <= hi {
+			out = append(out, interval{lo: lo, hi: hi})
+		}
+		if x[i].hi < y[j].hi {
+			i++
+		} else {
+			j++
+		}
+	}
+	return out
+}
+
+// subtractRuns returns the runs covering x but not y.
+func subtractRuns(x, y []interval) []interval {
+	var out []interval
+	j := 0
+	for _, run := range x {
+		// skip runs of y that end before this one starts.
+		for j < len(y) && y[j].hi < run.lo {
+			j++
+		}
+		lo := run.lo
+		for k := j; k < len(y) && y[k].lo <= run.hi; k++ {
+			if y[k].lo > lo {
+				out = append(out, interval{lo: lo, hi: y[k].lo - 1})
+			}
+			lo = maxInt(lo, y[k].hi+1)
+		}
+		if lo <= run.hi {
+			out = append(out, interval{lo: lo, hi: run.hi})
+		}
+	}
+	return out
+}
+
+// xorRuns returns the runs covering exactly one of x and y.
+func xorRuns(x, y []interval) []interval {
+	return unionRuns(subtractRuns(x, y), subtractRuns(y, x))
+}
+
+// overlapRuns returns the number of hexes in both a and b.
+func overlapRuns(a, b *runStore) int {
+	count := 0
+	i, j := 0, 0
+	for i < len(a.rows) && j < len(b.rows) {
+		switch {
+		case a.rows[i].r < b.rows[j].r:
+			i++
+		case b.rows[j].r < a.rows[i].r:
+			j++
+		default:
+			for _, run := range intersectRuns(a.rows[i].runs, b.rows[j].runs) {
+				count += run.length()
+			}
+			i++
+			j++
+		}
+	}
+	return count
+}
@@ -0,0 +1,194 @@
+package area
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/erinpentecost/hex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// asRuns returns a copy of a that is always kept as intervals.
+func asRuns(a *Area) *Area {
+	return (&Area{hexes: runsFrom(a.stored())}).ensureBounds()
+}
+
+func isRuns(a *Area) bool {
+	_, ok := a.hexes.(*runStore)
+	return ok
+}
+
+// randomRuns makes an area out of random runs in a few rows.
+func randomRuns(rng *rand.Rand, rows, runs int) *Area {
+	a := NewArea()
+	for i := 0; i < runs; i++ {
+		r := rng.Int63n(int64(rows))
+		lo := rng.Int63n(400) - 200
+		for q := lo; q < lo+rng.Int63n(40); q++ {
+			a.Add(hex.Hex{Q: q, R: r})
+		}
+	}
+	return a
+}
+
+// continent makes a huge area of long runs without visiting every hex.
+func continent(rows, width int64, shift int64) *Area {
+	s := &runStore{}
+	for r := int64(0); r < rows; r++ {
+		lo := shift + (r*7919)%(width/4)
+		runs := []interval{
+			{lo: lo, hi: lo + width/3},
+			{lo: lo + width/2, hi: lo + width},
+		}
+		s.rows = append(s.rows, runRow{r: r, runs: runs})
+		for _, run := range runs {
+			s.count += run.length()
+		}
+	}
+	return (&Area{hexes: s}).ensureBounds()
+}
+
+func TestRunStoreAddRemove(t *testing.T) {
+	rng := rand.New(rand.NewSource(24))
+	s := &runStore{}
+	expected := make(map[hex.Hex]bool)
+	for i := 0; i < 5000; i++ {
+		h := hex.Hex{Q: rng.Int63n(41) - 20, R: rng.Int63n(5)}
+		if rng.Intn(3) == 0 {
+			s.remove(h)
+			delete(expected, h)
+		} else {
+			s.add(h)
+			expected[h] = true
+		}
+		require.Equal(t, len(expected), s.size())
+	}
+
+	for q := int64(-25); q <= 25; q++ {
+		for r := int64(-1); r <= 6; r++ {
+			h := hex.Hex{Q: q, R: r}
+			require.Equal(t, expected[h], s.has(h), h)
+		}
+	}
+
+	// runs are sorted, don't touch, and rows aren't empty.
+	for i, row := range s.rows {
+		if i > 0 {
+			assert.Less(t, s.rows[i-1].r, row.r)
+		}
+		require.NotEmpty(t, row.runs)
+		for j, run := range row.runs {
+			assert.LessOrEqual(t, run.lo, run.hi)
+			if j > 0 {
+				assert.Less(t, row.runs[j-1].hi+1, run.lo)
+			}
+		}
+	}
+}
+
+func TestRunsLossless(t *testing.T) {
+	rng := rand.New(rand.NewSource(124))
+	for i := 0; i < 20; i++ {
+		m := asMap(randomRuns(rng, 10, 30))
+		runs := asRuns(m)
+		require.True(t, isRuns(runs))
+		assert.Equal(t, m.Size(), runs.Size())
+		assertSameHexes(t, m, runs)
+		assertSameHexes(t, m, asMap(runs))
+		requireSameBounds(t, runs)
+
+		bits := asBits(m)
+		assertSameHexes(t, m, asRuns(bits))
+		assert.Equal(t, countRuns(m.hexes), countRuns(bits.hexes))
+		assert.Equal(t, countRuns(m.hexes), countRuns(runs.hexes))
+	}
+
+	assert.Equal(t, 0, asRuns(NewArea()).Size())
+}
+
+func TestRunsMatchMap(t *testing.T) {
+	rng := rand.New(rand.NewSource(224))
+	for i := 0; i < 30; i++ {
+		ma, mb := asMap(randomRuns(rng, 8, 20)), asMap(randomRuns(rng, 8, 20))
+		a, b := asRuns(ma), asRuns(mb)
+
+		assertSameHexes(t, ma.Union(mb).Build(), a.Union(b).Build())
+		assertSameHexes(t, ma.Intersection(mb).Build(), a.Intersection(b).Build())
+		assertSameHexes(t, ma.Subtract(mb).Build(), a.Subtract(b).Build())
+		assertSameHexes(t, mb.Subtract(ma).Build(), b.Subtract(a).Build())
+		assertSameHexes(t, ma.Xor(mb).Build(), a.Xor(b).Build())
+		assertSameHexes(t, ma.Xor(mb).Build(), a.Xor(mb).Build())
+
+		// mixed stores work too.
+		assertSameHexes(t, ma.Subtract(mb).Build(), a.Subtract(mb).Build())
+		assertSameHexes(t, ma.Intersection(mb).Build(), ma.Intersection(b).Build())
+
+		assert.Equal(t, ma.CheckBounding(mb), a.CheckBounding(b))
+		assert.Equal(t, ma.CheckBounding(mb), a.CheckBounding(mb))
+		assert.Equal(t, ma.CheckBounding(mb), ma.CheckBounding(b))
+		assert.Equal(t, Equals, a.CheckBounding(ma))
+
+		for q := int64(-250); q <= 250; q++ {
+			h := hex.Hex{Q: q, R: rng.Int63n(10) - 1}
+			require.Equal(t, ma.ContainsHexes(h), a.ContainsHexes(h), h)
+		}
+	}
+}
+
+func TestContinent(t *testing.T) {
+	a := continent(2000, 100000, 0)
+	b := continent(2000, 100000, 5000)
+	require.True(t, isRuns(a))
+	assert.Equal(t, Overlap, a.CheckBounding(b))
+	assert.Equal(t, Contains, a.CheckBounding(a.Subtract(b).Build()))
+
+	moved := a.Translate(hex.Hex{Q: 3, R: -9}).Build()
+	require.True(t, isRuns(moved))
+	assert.Equal(t, a.Size(), moved.Size())
+	assert.True(t, moved.ContainsHexes(hex.Hex{Q: 3, R: -9}))
+	assert.False(t, moved.ContainsHexes(hex.Origin()))
+
+	u := a.Union(b).Build()
+	i := a.Intersection(b).Build()
+	require.True(t, isRuns(u))
+	require.True(t, isRuns(i))
+	assert.Equal(t, a.Size()+b.Size(), u.Size()+i.Size())
+}
+
+func benchmarkContinent(b *testing.B, fn func(a, c *Area) *Area) {
+	x := continent(10000, 1000000, 0)
+	y := continent(10000, 1000000, 30000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fn(x, y)
+	}
+}
+
+func BenchmarkUnionRuns(b *testing.B)        { benchmarkContinent(b, unionFn) }
+func BenchmarkIntersectionRuns(b *testing.B) { benchmarkContinent(b, intersectionFn) }
+func BenchmarkSubtractRuns(b *testing.B)     { benchmarkContinent(b, subtractFn) }
+
+func BenchmarkCheckBoundingRuns(b *testing.B) {
+	x := continent(10000, 1000000, 0)
+	y := continent(10000, 1000000, 30000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.CheckBounding(y)
+	}
+}
+
+func TestContinentBinary(t *testing.T) {
+	a := continent(2000, 100000, 0)
+	b, err := a.MarshalBinary()
+	require.NoError(t, err)
+	// a few bytes per run, not per hex.
+	assert.Less(t, len(b), 20*countRuns(a.hexes))
+
+	var back Area
+	require.NoError(t, back.UnmarshalBinary(b))
+	require.True(t, isRuns(&back))
+	assert.True(t, a.Equals(&back))
+}
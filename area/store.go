@@ -0,0 +1,184 @@
+package area
+
+import (
+	"math"
+
+	"github.com/erinpentecost/hex"
+)
+
+// store holds the hexes in an Area.
+//
+// Areas pick a store based on how densely packed their hexes are.
+// Callers never see which one is in use.
+type store interface {
+	has(h hex.Hex) bool
+	add(h hex.Hex)
+	remove(h hex.Hex)
+	size() int
+	// each calls fn with every hex until fn returns false.
+	// It returns false if it stopped early.
+	each(fn func(hex.Hex) bool) bool
+	clone() store
+}
+
+// bounder is a store that can find its own bounding box
+// faster than visiting every hex.
+type bounder interface {
+	bounds() (minR, maxR, minQ, maxQ int64, ok bool)
+}
+
+var (
+	_ store = mapStore{}
+	_ store = (*bitStore)(nil)
+	_ store = (*runStore)(nil)
+)
+
+// mapStore is the default store. It works well for any shape,
+// but costs a lot of memory per hex.
+type mapStore map[hex.Hex]struct{}
+
+func (m mapStore) has(h hex.Hex) bool {
+	_, ok := m[h]
+	return ok
+}
+
+func (m mapStore) add(h hex.Hex) {
+	m[h] = exists
+}
+
+func (m mapStore) remove(h hex.Hex) {
+	delete(m, h)
+}
+
+func (m mapStore) size() int {
+	return len(m)
+}
+
+func (m mapStore) each(fn func(hex.Hex) bool) bool {
+	for k := range m {
+		if !fn(k) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m mapStore) clone() store {
+	c := make(mapStore, len(m))
+	for k := range m {
+		c[k] = exists
+	}
+	return c
+}
+
+const (
+	// denseMinSize is the smallest area that is worth storing
+	// as anything but a map.
+	denseMinSize = 1024
+	// denseMaxSpread is the most bounding box hexes per area hex
+	// that are allowed in a bitset.
+	denseMaxSpread = 64
+	// runMinLength is the shortest average run of hexes in a row
+	// that is worth storing as intervals. At this length, an interval
+	// takes about as much memory as the bits it covers.
+	runMinLength = 128
+)
+
+// isDense returns true if an area of size hexes in the given
+// bounding box should be stored as a bitset.
+func isDense(size int, minR, maxR, minQ, maxQ int64) bool {
+	if size < denseMinSize {
+		return false
+	}
+	limit := int64(math.MaxInt64)
+	if int64(size) < limit/denseMaxSpread {
+		limit = int64(size) * denseMaxSpread
+	}
+	// spans that wrap around are far too big.
+	rows, cols := maxR-minR, maxQ-minQ
+	if rows < 0 || cols < 0 || rows >= limit || cols >= limit {
+		return false
+	}
+	rows, cols = rows+1, cols+1
+	return rows <= limit/cols
+}
+
+// newStore returns an empty store suited for about size hexes
+// in the given bounding box.
+func newStore(size int, minR, maxR, minQ, maxQ int64) store {
+	if isDense(size, minR, maxR, minQ, maxQ) {
+		return newBitStore(minR, maxR, minQ, maxQ)
+	}
+	return make(mapStore, size)
+}
+
+// makeRoom moves the hexes out of a bitset if adding k would
+// make the bitset grow too sparse, so one far away hex can't
+// blow up the size of the bitset.
+func (a *Area) makeRoom(k hex.Hex) {
+	b, ok := a.hexes.(*bitStore)
+	if !ok {
+		return
+	}
+	if _, _, inside := b.locate(k); inside {
+		return
+	}
+	minR, maxR := minInt(b.minR, k.R), maxInt(b.maxR, k.R)
+	minQ, maxQ := minInt(b.minW<<6, k.Q), maxInt(b.maxW<<6+63, k.Q)
+	if isDense(b.count+1, minR, maxR, minQ, maxQ) {
+		return
+	}
+	m := make(mapStore, b.count+1)
+	b.each(func(h hex.Hex) bool {
+		m[h] = exists
+		return true
+	})
+	a.hexes = m
+}
+
+// pack moves the hexes into whichever store suits them best.
+//
+// Areas made of long runs are kept as intervals,
+// other dense areas as bitsets, and everything else in a map.
+func (a *Area) pack() *Area {
+	a.ensureBounds()
+	size := a.Size()
+	var want store
+	switch {
+	case size < denseMinSize:
+		want = mapStore{}
+	case countRuns(a.hexes)*runMinLength <= size:
+		want = &runStore{}
+	case isDense(size, a.minR, a.maxR, a.minQ, a.maxQ):
+		want = &bitStore{}
+	default:
+		want = mapStore{}
+	}
+
+	switch want.(type) {
+	case mapStore:
+		if _, ok := a.hexes.(mapStore); ok || a.hexes == nil {
+			return a
+		}
+		packed := make(mapStore, size)
+		a.hexes.each(func(h hex.Hex) bool {
+			packed[h] = exists
+			return true
+		})
+		a.hexes = packed
+	case *runStore:
+		if _, ok := a.hexes.(*runStore); !ok {
+			a.hexes = runsFrom(a.hexes)
+		}
+	case *bitStore:
+		if _, ok := a.hexes.(*bitStore); !ok {
+			packed := newBitStore(a.minR, a.maxR, a.minQ, a.maxQ)
+			a.hexes.each(func(h hex.Hex) bool {
+				packed.add(h)
+				return true
+			})
+			a.hexes = packed
+		}
+	}
+	return a
+}
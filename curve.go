@@ -0,0 +1,115 @@
+package hex
+
+import (
+	"fmt"
+	"math"
+)
+
+// Space-filling curve keys pack a hex into a single uint64 such that
+// hexes that are close together usually have keys that are close together.
+// This makes them good keys for key-value stores and for sorting.
+//
+// Keys are built from Q and R, which must each fit in an int32.
+// The key functions panic if they don't.
+
+// keyOffset shifts int32 coordinates into the uint32 range
+// so that ordering is preserved.
+const keyOffset = -math.MinInt32
+
+// keyCurveSize is the width of the grid covered by the curves.
+const keyCurveSize = uint64(1) << 32
+
+func toKeyCoords(h Hex) (uint64, uint64) {
+	if h.Q < math.MinInt32 || h.Q > math.MaxInt32 || h.R < math.MinInt32 || h.R > math.MaxInt32 {
+		panic(fmt.Sprintf("hex %s is too far from the origin to have a key", h))
+	}
+	return uint64(h.Q + keyOffset), uint64(h.R + keyOffset)
+}
+
+func fromKeyCoords(x, y uint64) Hex {
+	return Hex{Q: int64(x) - keyOffset, R: int64(y) - keyOffset}
+}
+
+// spreadBits moves the low 32 bits of v into the even bits of the result.
+func spreadBits(v uint64) uint64 {
+	v &= 0x00000000ffffffff
+	v = (v | (v << 16)) & 0x0000ffff0000ffff
+	v = (v | (v << 8)) & 0x00ff00ff00ff00ff
+	v = (v | (v << 4)) & 0x0f0f0f0f0f0f0f0f
+	v = (v | (v << 2)) & 0x3333333333333333
+	v = (v | (v << 1)) & 0x5555555555555555
+	return v
+}
+
+// compactBits is the inverse of spreadBits.
+func compactBits(v uint64) uint64 {
+	v &= 0x5555555555555555
+	v = (v | (v >> 1)) & 0x3333333333333333
+	v = (v | (v >> 2)) & 0x0f0f0f0f0f0f0f0f
+	v = (v | (v >> 4)) & 0x00ff00ff00ff00ff
+	v = (v | (v >> 8)) & 0x0000ffff0000ffff
+	v = (v | (v >> 16)) & 0x00000000ffffffff
+	return v
+}
+
+// MortonKey returns the position of the hex along a Z-order curve.
+// Q is stored in the even bits and R in the odd bits.
+//
+// This panics if Q or R doesn't fit in an int32.
+func (h Hex) MortonKey() uint64 {
+	x, y := toKeyCoords(h)
+	return spreadBits(x) | spreadBits(y)<<1
+}
+
+// HexFromMortonKey is the inverse of MortonKey.
+func HexFromMortonKey(key uint64) Hex {
+	return fromKeyCoords(compactBits(key), compactBits(key>>1))
+}
+
+// hilbertRotate flips a quadrant so the curve lines up with its neighbors.
+func hilbertRotate(n, x, y, rx, ry uint64) (uint64, uint64) {
+	if ry == 0 {
+		if rx == 1 {
+			x = n - 1 - x
+			y = n - 1 - y
+		}
+		return y, x
+	}
+	return x, y
+}
+
+// HilbertKey returns the position of the hex along a Hilbert curve.
+// Hilbert keys have better locality than Morton keys, but
+// are slower to compute.
+//
+// This panics if Q or R doesn't fit in an int32.
+func (h Hex) HilbertKey() uint64 {
+	x, y := toKeyCoords(h)
+	var key uint64
+	for s := keyCurveSize / 2; s > 0; s /= 2 {
+		var rx, ry uint64
+		if x&s > 0 {
+			rx = 1
+		}
+		if y&s > 0 {
+			ry = 1
+		}
+		key += s * s * ((3 * rx) ^ ry)
+		x, y = hilbertRotate(keyCurveSize, x, y, rx, ry)
+	}
+	return key
+}
+
+// HexFromHilbertKey is the inverse of HilbertKey.
+func HexFromHilbertKey(key uint64) Hex {
+	var x, y uint64
+	for s := uint64(1); s < keyCurveSize; s *= 2 {
+		rx := 1 & (key / 2)
+		ry := 1 & (key ^ rx)
+		x, y = hilbertRotate(s, x, y, rx, ry)
+		x += s * rx
+		y += s * ry
+		key /= 4
+	}
+	return fromKeyCoords(x, y)
+}
@@ -0,0 +1,80 @@
+package hex
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKeyHexes() []Hex {
+	hexes := Spiral(Origin(), 6)
+	hexes = append(hexes,
+		Hex{Q: math.MaxInt32, R: math.MinInt32},
+		Hex{Q: math.MinInt32, R: math.MaxInt32},
+		Hex{Q: math.MaxInt32, R: math.MaxInt32},
+		Hex{Q: math.MinInt32, R: math.MinInt32},
+	)
+	rng := rand.New(rand.NewSource(13))
+	for i := 0; i < 1000; i++ {
+		hexes = append(hexes, Hex{Q: int64(int32(rng.Uint32())), R: int64(int32(rng.Uint32()))})
+	}
+	return hexes
+}
+
+func TestMortonKey(t *testing.T) {
+	for _, h := range testKeyHexes() {
+		require.Equal(t, h, HexFromMortonKey(h.MortonKey()))
+	}
+
+	assert.Less(t, Hex{Q: -1, R: -1}.MortonKey(), Origin().MortonKey())
+	assert.Less(t, Origin().MortonKey(), Hex{Q: 1, R: 0}.MortonKey())
+	assert.Less(t, Hex{Q: 1, R: 0}.MortonKey(), Hex{Q: 0, R: 1}.MortonKey())
+	assert.Equal(t, uint64(0), HexFromMortonKey(0).MortonKey())
+	assert.Equal(t, uint64(math.MaxUint64), HexFromMortonKey(math.MaxUint64).MortonKey())
+}
+
+func TestHilbertKey(t *testing.T) {
+	for _, h := range testKeyHexes() {
+		require.Equal(t, h, HexFromHilbertKey(h.HilbertKey()))
+	}
+
+	// consecutive keys are next to each other
+	// on the Q/R grid.
+	rng := rand.New(rand.NewSource(17))
+	for i := 0; i < 1000; i++ {
+		key := rng.Uint64() >> 1
+		a := HexFromHilbertKey(key)
+		b := HexFromHilbertKey(key + 1)
+		require.EqualValues(t, 1, absInt(a.Q-b.Q)+absInt(a.R-b.R), "key %d", key)
+	}
+}
+
+func TestKeyPanics(t *testing.T) {
+	assert.Panics(t, func() { Hex{Q: math.MaxInt32 + 1}.MortonKey() })
+	assert.Panics(t, func() { Hex{R: math.MinInt32 - 1}.HilbertKey() })
+}
+
+func TestCurveSort(t *testing.T) {
+	hexes := Spiral(Hex{Q: 3, R: -2}, 10)
+
+	sort.Sort(MortonSort(hexes))
+	assert.True(t, sort.SliceIsSorted(hexes, func(i, j int) bool {
+		return hexes[i].MortonKey() < hexes[j].MortonKey()
+	}))
+
+	sort.Sort(HilbertSort(hexes))
+	assert.True(t, sort.SliceIsSorted(hexes, func(i, j int) bool {
+		return hexes[i].HilbertKey() < hexes[j].HilbertKey()
+	}))
+
+	// the Hilbert curve has good locality: steps along it are short.
+	total := int64(0)
+	for i := 1; i < len(hexes); i++ {
+		total += hexes[i-1].DistanceTo(hexes[i])
+	}
+	assert.Less(t, total, int64(2*len(hexes)))
+}
@@ -0,0 +1,130 @@
+package hex
+
+import "fmt"
+
+// DoubledType selects which axis of a doubled coordinate is doubled.
+type DoubledType byte
+
+const (
+	// DoubledWidth doubles the column step. Use with pointy-top hexes.
+	DoubledWidth DoubledType = iota
+	// DoubledHeight doubles the row step. Use with flat-top hexes.
+	DoubledHeight
+)
+
+func (d DoubledType) String() string {
+	switch d {
+	case DoubledWidth:
+		return "doubled-width"
+	case DoubledHeight:
+		return "doubled-height"
+	default:
+		return "?"
+	}
+}
+
+// DoubledCoord is a coordinate in a doubled grid.
+//
+// Col+Row is always even for a valid doubled coordinate.
+// A DoubledCoord only has meaning alongside the DoubledType
+// it was created with.
+type DoubledCoord struct {
+	Col int64
+	Row int64
+}
+
+var doubledDirections = [2][6]DoubledCoord{
+	// DoubledWidth
+	{{Col: 2, Row: 0}, {Col: 1, Row: -1}, {Col: -1, Row: -1}, {Col: -2, Row: 0}, {Col: -1, Row: 1}, {Col: 1, Row: 1}},
+	// DoubledHeight
+	{{Col: 1, Row: 1}, {Col: 1, Row: -1}, {Col: 0, Row: -2}, {Col: -1, Row: -1}, {Col: -1, Row: 1}, {Col: 0, Row: 2}},
+}
+
+// ToDoubled converts the hex into a doubled coordinate.
+func (h Hex) ToDoubled(t DoubledType) DoubledCoord {
+	switch t {
+	case DoubledWidth:
+		return DoubledCoord{
+			Col: 2*h.Q + h.R,
+			Row: h.R,
+		}
+	case DoubledHeight:
+		return DoubledCoord{
+			Col: h.Q,
+			Row: 2*h.R + h.Q,
+		}
+	}
+	panic("unknown doubled type")
+}
+
+// ToHex converts the doubled coordinate back into a hex.
+// t must be the same type used to create the doubled coordinate.
+func (d DoubledCoord) ToHex(t DoubledType) Hex {
+	switch t {
+	case DoubledWidth:
+		return Hex{
+			Q: (d.Col - d.Row) / 2,
+			R: d.Row,
+		}
+	case DoubledHeight:
+		return Hex{
+			Q: d.Col,
+			R: (d.Row - d.Col) / 2,
+		}
+	}
+	panic("unknown doubled type")
+}
+
+// DoubledDirection returns a new doubled coord offset from the origin
+// in the given direction, which is a number from 0 to 5, inclusive.
+// Directions match the ones used by Direction.
+func DoubledDirection(t DoubledType, direction int) DoubledCoord {
+	if t != DoubledWidth && t != DoubledHeight {
+		panic("unknown doubled type")
+	}
+	return doubledDirections[t][BoundFacing(direction)]
+}
+
+// Add combines two doubled coordinates.
+func (d DoubledCoord) Add(x DoubledCoord) DoubledCoord {
+	return DoubledCoord{
+		Col: d.Col + x.Col,
+		Row: d.Row + x.Row,
+	}
+}
+
+// Neighbor returns the neighbor in the given direction.
+func (d DoubledCoord) Neighbor(t DoubledType, direction int) DoubledCoord {
+	return d.Add(DoubledDirection(t, direction))
+}
+
+// DistanceTo returns the distance between two doubled coordinates.
+//
+// This is the same value as Hex.DistanceTo.
+func (d DoubledCoord) DistanceTo(t DoubledType, x DoubledCoord) int64 {
+	dCol := absInt(d.Col - x.Col)
+	dRow := absInt(d.Row - x.Row)
+	switch t {
+	case DoubledWidth:
+		return dRow + maxInt(0, (dCol-dRow)/2)
+	case DoubledHeight:
+		return dCol + maxInt(0, (dRow-dCol)/2)
+	}
+	panic("unknown doubled type")
+}
+
+// LineTo returns all doubled coordinates in a line from d to x, inclusive.
+// This follows the same path as Hex.LineTo.
+func (d DoubledCoord) LineTo(t DoubledType, x DoubledCoord) []DoubledCoord {
+	hexLine := d.ToHex(t).LineTo(x.ToHex(t))
+	line := make([]DoubledCoord, len(hexLine))
+	for i, h := range hexLine {
+		line[i] = h.ToDoubled(t)
+	}
+	return line
+}
+
+// String converts the doubled coordinate to a string.
+func (d DoubledCoord) String() string {
+	return fmt.Sprintf("[%v, %v]", d.Col, d.Row)
+}
@@ -0,0 +1,61 @@
+package hex
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var allDoubledTypes = []DoubledType{DoubledWidth, DoubledHeight}
+
+func TestDoubledRoundTrip(t *testing.T) {
+	testHexes := HexArea(Origin(), 10)
+	for _, dt := range allDoubledTypes {
+		t.Run(dt.String(), func(t *testing.T) {
+			for _, h := range testHexes {
+				h = h.Add(Hex{Q: 5, R: -9})
+				d := h.ToDoubled(dt)
+				require.Zero(t, (d.Col+d.Row)&1, "invalid doubled coord %s for %s", d, h)
+				require.Equal(t, h, d.ToHex(dt), "doubled %s", d)
+			}
+		})
+	}
+}
+
+func TestDoubledNeighbor(t *testing.T) {
+	testHexes := HexArea(Origin(), 5)
+	for _, dt := range allDoubledTypes {
+		t.Run(dt.String(), func(t *testing.T) {
+			for _, h := range testHexes {
+				d := h.ToDoubled(dt)
+				for i := -6; i < 12; i++ {
+					assert.Equal(t, h.Neighbor(i), d.Neighbor(dt, i).ToHex(dt), fmt.Sprintf("neighbor %d of %s", i, h))
+				}
+			}
+		})
+	}
+}
+
+func TestDoubledDistanceAndLine(t *testing.T) {
+	testHexes := HexArea(Hex{Q: -2, R: 1}, 4)
+	for _, dt := range allDoubledTypes {
+		t.Run(dt.String(), func(t *testing.T) {
+			for _, a := range testHexes {
+				for _, b := range testHexes {
+					da := a.ToDoubled(dt)
+					db := b.ToDoubled(dt)
+					require.Equal(t, a.DistanceTo(b), da.DistanceTo(dt, db), "distance from %s to %s", a, b)
+
+					hexLine := a.LineTo(b)
+					line := da.LineTo(dt, db)
+					require.Len(t, line, len(hexLine))
+					for i := range line {
+						require.Equal(t, hexLine[i], line[i].ToHex(dt))
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package hex
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	_ encoding.TextMarshaler   = Hex{}
+	_ encoding.TextUnmarshaler = (*Hex)(nil)
+	_ json.Marshaler           = Hex{}
+	_ json.Unmarshaler         = (*Hex)(nil)
+
+	_ encoding.TextMarshaler   = HexFractional{}
+	_ encoding.TextUnmarshaler = (*HexFractional)(nil)
+	_ json.Marshaler           = HexFractional{}
+	_ json.Unmarshaler         = (*HexFractional)(nil)
+)
+
+// ErrBadHexText is returned when text can't be parsed into a hex.
+var ErrBadHexText = errors.New("hex text must look like \"q,r\"")
+
+// ErrBadCubeCoordinate is returned when a decoded hex has an S
+// coordinate that doesn't agree with Q and R.
+var ErrBadCubeCoordinate = errors.New("hex coordinates must satisfy q+r+s=0")
+
+// splitText splits "q,r" into its two parts.
+func splitText(text []byte) (string, string, error) {
+	parts := strings.Split(string(text), ",")
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("%w: got %q", ErrBadHexText, text)
+	}
+	return strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]), nil
+}
+
+// MarshalText encodes the hex as "q,r".
+//
+// This lets hexes be used as keys in JSON maps.
+func (h Hex) MarshalText() ([]byte, error) {
+	b := strconv.AppendInt(nil, h.Q, 10)
+	b = append(b, ',')
+	return strconv.AppendInt(b, h.R, 10), nil
+}
+
+// UnmarshalText decodes a hex made by MarshalText.
+func (h *Hex) UnmarshalText(text []byte) error {
+	qs, rs, err := splitText(text)
+	if err != nil {
+		return err
+	}
+	q, err := strconv.ParseInt(qs, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrBadHexText, err)
+	}
+	r, err := strconv.ParseInt(rs, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrBadHexText, err)
+	}
+	h.Q, h.R = q, r
+	return nil
+}
+
+// hexJSON is the JSON layout of a Hex.
+// S is optional when decoding.
+type hexJSON struct {
+	Q int64
+	R int64
+	S *int64 `json:",omitempty"`
+}
+
+// MarshalJSON encodes the hex as {"Q":q,"R":r}.
+func (h Hex) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hexJSON{Q: h.Q, R: h.R})
+}
+
+// UnmarshalJSON decodes a hex from either {"Q":q,"R":r}
+// or the string form made by MarshalText.
+//
+// If S is present, it must agree with Q and R.
+func (h *Hex) UnmarshalJSON(data []byte) error {
+	if isJSONString(data) {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		return h.UnmarshalText([]byte(s))
+	}
+
+	var v hexJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.S != nil && v.Q+v.R+*v.S != 0 {
+		return fmt.Errorf("%w: got %s", ErrBadCubeCoordinate, data)
+	}
+	h.Q, h.R = v.Q, v.R
+	return nil
+}
+
+// MarshalText encodes the hex as "q,r".
+//
+// Coordinates are written with as many digits as needed
+// to decode back into the exact same value.
+func (h HexFractional) MarshalText() ([]byte, error) {
+	b := strconv.AppendFloat(nil, h.Q, 'g', -1, 64)
+	b = append(b, ',')
+	return strconv.AppendFloat(b, h.R, 'g', -1, 64), nil
+}
+
+// UnmarshalText decodes a hex made by MarshalText.
+func (h *HexFractional) UnmarshalText(text []byte) error {
+	qs, rs, err := splitText(text)
+	if err != nil {
+		return err
+	}
+	q, err := strconv.ParseFloat(qs, 64)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrBadHexText, err)
+	}
+	r, err := strconv.ParseFloat(rs, 64)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrBadHexText, err)
+	}
+	h.Q, h.R = q, r
+	return nil
+}
+
+// hexFractionalJSON is the JSON layout of a HexFractional.
+// S is optional when decoding.
+type hexFractionalJSON struct {
+	Q float64
+	R float64
+	S *float64 `json:",omitempty"`
+}
+
+// MarshalJSON encodes the hex as {"Q":q,"R":r}.
+func (h HexFractional) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hexFractionalJSON{Q: h.Q, R: h.R})
+}
+
+// UnmarshalJSON decodes a hex from either {"Q":q,"R":r}
+// or the string form made by MarshalText.
+//
+// If S is present, it must agree with Q and R.
+func (h *HexFractional) UnmarshalJSON(data []byte) error {
+	if isJSONString(data) {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		return h.UnmarshalText([]byte(s))
+	}
+
+	var v hexFractionalJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.S != nil && math.Abs(v.Q+v.R+*v.S) > geometryEpsilon {
+		return fmt.Errorf("%w: got %s", ErrBadCubeCoordinate, data)
+	}
+	h.Q, h.R = v.Q, v.R
+	return nil
+}
+
+func isJSONString(data []byte) bool {
+	trimmed := strings.TrimSpace(string(data))
+	return len(trimmed) > 0 && trimmed[0] == '"'
+}
@@ -0,0 +1,78 @@
+package hex
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHexText(t *testing.T) {
+	for _, h := range Spiral(Hex{Q: -3, R: 7}, 3) {
+		text, err := h.MarshalText()
+		require.NoError(t, err)
+
+		var back Hex
+		require.NoError(t, back.UnmarshalText(text))
+		assert.Equal(t, h, back)
+	}
+
+	text, err := Hex{Q: -4, R: 12}.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "-4,12", string(text))
+
+	var h Hex
+	assert.ErrorIs(t, h.UnmarshalText([]byte("1,2,3")), ErrBadHexText)
+	assert.ErrorIs(t, h.UnmarshalText([]byte("1,x")), ErrBadHexText)
+	assert.ErrorIs(t, h.UnmarshalText([]byte("")), ErrBadHexText)
+}
+
+func TestHexJSON(t *testing.T) {
+	b, err := json.Marshal(Hex{Q: 2, R: -5})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"Q":2,"R":-5}`, string(b))
+
+	var h Hex
+	require.NoError(t, json.Unmarshal([]byte(`{"Q":2,"R":-5}`), &h))
+	assert.Equal(t, Hex{Q: 2, R: -5}, h)
+	require.NoError(t, json.Unmarshal([]byte(`{"Q":1,"R":1,"S":-2}`), &h))
+	assert.Equal(t, Hex{Q: 1, R: 1}, h)
+	require.NoError(t, json.Unmarshal([]byte(`"3,4"`), &h))
+	assert.Equal(t, Hex{Q: 3, R: 4}, h)
+
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"Q":1,"R":1,"S":1}`), &h), ErrBadCubeCoordinate)
+}
+
+func TestHexFractionalEncoding(t *testing.T) {
+	h := HexFractional{Q: 1.0 / 3.0, R: -2.5e-7}
+
+	text, err := h.MarshalText()
+	require.NoError(t, err)
+	var back HexFractional
+	require.NoError(t, back.UnmarshalText(text))
+	assert.Equal(t, h, back)
+
+	b, err := json.Marshal(h)
+	require.NoError(t, err)
+	back = HexFractional{}
+	require.NoError(t, json.Unmarshal(b, &back))
+	assert.Equal(t, h, back)
+
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"Q":0.5,"R":0.5,"S":0}`), &back), ErrBadCubeCoordinate)
+}
+
+func TestHexMapJSON(t *testing.T) {
+	m := map[Hex]string{
+		Origin():          "origin",
+		Hex{Q: -1, R: 3}:  "a",
+		Hex{Q: 10, R: -7}: "b",
+	}
+	b, err := json.Marshal(m)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"0,0":"origin","-1,3":"a","10,-7":"b"}`, string(b))
+
+	back := make(map[Hex]string)
+	require.NoError(t, json.Unmarshal(b, &back))
+	assert.Equal(t, m, back)
+}
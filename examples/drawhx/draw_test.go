@@ -15,6 +15,7 @@ import (
 
 func TestDraw(t *testing.T) {
 	cc := NewCamera(800,
+		hex.DefaultLayout(),
 		area.BigHex(hex.Origin(), 2).Subtract(area.BigHex(hex.Origin(), 1)).Build(),
 		func(h hex.Hex) string { return "" },
 	)
@@ -26,6 +27,22 @@ func TestDraw(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestDrawFlat(t *testing.T) {
+	layout := hex.DefaultLayout()
+	layout.Orientation = hex.FlatTop
+	cc := NewCamera(800,
+		layout,
+		area.BigHex(hex.Origin(), 2).Subtract(area.BigHex(hex.Origin(), 1)).Build(),
+		func(h hex.Hex) string { return "" },
+	)
+	img := cc.Draw()
+
+	// the center of the image should be the origin hex
+	center := cc.ScreenToHex(cc.imageLenX/2, cc.imageLenY/2).ToHex()
+	assert.Equal(t, hex.Origin(), center)
+	assert.Equal(t, AreaColor(hex.Origin(), cc.area), img.RGBAAt(cc.imageLenX/2, cc.imageLenY/2))
+}
+
 func createLogoPoints() []hex.Hex {
 	h := []hex.Hex{
 		{Q: 2, R: -2},
@@ -21,16 +21,19 @@ import (
 
 var outFile string
 var width int
+var flat bool
 
 func init() {
 	flag.StringVar(&outFile, "file", "", "png file to save the image to.")
 
 	flag.IntVar(&width, "w", 500, "width of the image")
 
+	flag.BoolVar(&flat, "flat", false, "draw flat-top hexes instead of pointy-top hexes")
 }
 
 type annotatedHex struct {
-	hex.Hex
+	Q     int64
+	R     int64
 	Label string
 }
 
@@ -70,11 +73,16 @@ func main() {
 	labelLookup := make(map[hex.Hex]string)
 	hexes := make([]hex.Hex, len(wrappedHexes))
 	for i, h := range wrappedHexes {
-		hexes[i] = h.Hex
-		labelLookup[h.Hex] = h.Label
+		hexes[i] = hex.Hex{Q: h.Q, R: h.R}
+		labelLookup[hexes[i]] = h.Label
 	}
 
-	cc := NewCamera(width, area.NewArea(hexes...), func(h hex.Hex) string { return labelLookup[h] })
+	layout := hex.DefaultLayout()
+	if flat {
+		layout.Orientation = hex.FlatTop
+	}
+
+	cc := NewCamera(width, layout, area.NewArea(hexes...), func(h hex.Hex) string { return labelLookup[h] })
 	img := cc.Draw()
 
 	os.Stderr.WriteString("saving...\n")
@@ -100,30 +108,39 @@ type Camera struct {
 	minQ      int64
 	maxQ      int64
 
+	layout   hex.Layout
 	labeller func(hex.Hex) string
 	area     *area.Area
 }
 
 // NewCamera creates a new camera object
-func NewCamera(width int, area *area.Area, labeller func(hex.Hex) string) Camera {
+func NewCamera(width int, layout hex.Layout, area *area.Area, labeller func(hex.Hex) string) Camera {
 
 	// find world bounds
 	minR, maxR, minQ, maxQ, err := area.Bounds()
 	if err != nil {
 		log.Fatal(err)
 	}
-	//minR = minR - 2
-	//maxR = maxR + 2
 
-	// world bounds
-	topLeftX, topLeftY := hex.Hex{Q: minQ, R: minR}.ToHexFractional().ToCartesian()
-	bottomRightX, bottomRightY := hex.Hex{Q: maxQ, R: maxR}.ToHexFractional().ToCartesian()
-	_, addY := hex.Hex{Q: -1, R: 2}.ToHexFractional().ToCartesian()
-	addY = math.Abs(addY) * 1.5
-	worldX := bottomRightX - topLeftX
-	worldY := bottomRightY - topLeftY + addY
+	// world bounds are the corners of every hex in the area,
+	// padded out so the neighboring hexes show up too.
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, h := range area.Slice() {
+		for _, c := range layout.HexCorners(h) {
+			minX = math.Min(minX, c.X)
+			maxX = math.Max(maxX, c.X)
+			minY = math.Min(minY, c.Y)
+			maxY = math.Max(maxY, c.Y)
+		}
+	}
+	padX := math.Abs(layout.Size.X)
+	padY := math.Abs(layout.Size.Y)
+	minX, maxX = minX-padX, maxX+padX
+	minY, maxY = minY-padY, maxY+padY
 
-	worldDiag := math.Sqrt(float64(worldX*worldX + worldY*worldY))
+	worldX := maxX - minX
+	worldY := maxY - minY
 
 	// height is determined by aspect ratio of world space
 	height := int(float64(width) * worldY / worldX)
@@ -131,11 +148,10 @@ func NewCamera(width int, area *area.Area, labeller func(hex.Hex) string) Camera
 	imageLenX := width
 	imageLenY := height
 
-	imgDiag := math.Sqrt(float64(imageLenX*imageLenX + imageLenY*imageLenY))
-
-	centerX, centerY := hex.LerpHex(hex.Hex{Q: minQ, R: minR}, hex.Hex{Q: maxQ, R: maxR}, 0.5).ToHexFractional().ToCartesian()
+	centerX := (minX + maxX) / 2.0
+	centerY := (minY + maxY) / 2.0
 
-	hWidth := imgDiag / worldDiag
+	hWidth := float64(imageLenX) / worldX
 
 	wrappedLabeller := func(h hex.Hex) string {
 		if l := labeller(h); l != "" {
@@ -154,6 +170,7 @@ func NewCamera(width int, area *area.Area, labeller func(hex.Hex) string) Camera
 		maxR:      maxR,
 		minQ:      minQ,
 		maxQ:      maxQ,
+		layout:    layout,
 		area:      area,
 		labeller:  wrappedLabeller,
 	}
@@ -163,14 +180,14 @@ func NewCamera(width int, area *area.Area, labeller func(hex.Hex) string) Camera
 func (c Camera) ScreenToHex(x, y int) hex.HexFractional {
 	xM := (float64(x-c.imageLenX/2) / c.hWidth) + c.centerX
 	xY := (float64(y-c.imageLenY/2) / c.hWidth) + c.centerY
-	return hex.HexFractionalFromCartesian(xM, xY)
+	return c.layout.PixelToHex(hex.Point{X: xM, Y: xY})
 }
 
 // HexToScreen converts hex coord to screen coord.
 // returned value may be out of bounds.
 func (c Camera) HexToScreen(p hex.HexFractional) (x, y int) {
-	hx, hy := p.ToCartesian()
-	return int((hx-c.centerX)*c.hWidth) + c.imageLenX/2, int((hy-c.centerY)*c.hWidth) + c.imageLenY/2
+	hp := c.layout.HexToPixel(p)
+	return int((hp.X-c.centerX)*c.hWidth) + c.imageLenX/2, int((hp.Y-c.centerY)*c.hWidth) + c.imageLenY/2
 }
 
 // Grid draws a hex grid.
@@ -188,14 +205,15 @@ func (c Camera) Draw() *image.RGBA {
 	}
 
 	// label the hexes
-	if c.hWidth > 75.0 {
+	hexPixels := c.hWidth * math.Abs(c.layout.Size.X)
+	if hexPixels > 75.0 {
 		for h := range seen {
 			col := color.RGBA{0, 0, 0, 255}
 			if c.area.ContainsHexes(h) {
 				col = color.RGBA{200, 200, 200, 255}
 			}
 			hx, hy := c.HexToScreen(h.ToHexFractional())
-			addLabel(img, hx-int(c.hWidth)/2, hy, col, c.labeller(h))
+			addLabel(img, hx-int(hexPixels)/2, hy, col, c.labeller(h))
 		}
 	}
 
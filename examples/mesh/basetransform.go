@@ -10,13 +10,36 @@ var (
 )
 
 type BaseTransform struct {
-	area area.Area
+	area   area.Area
+	layout hex.Layout
+}
+
+// NewBaseTransform creates a transformer that raises the hexes in a
+// and positions everything with layout.
+func NewBaseTransform(a *area.Area, layout hex.Layout) *BaseTransform {
+	b := &BaseTransform{
+		layout: layout,
+	}
+	if a != nil {
+		b.area = *a
+	}
+	return b
+}
+
+// Layout returns the layout used to position hexes.
+// The zero value falls back to hex.DefaultLayout().
+func (b *BaseTransform) Layout() hex.Layout {
+	if b.layout == (hex.Layout{}) {
+		return hex.DefaultLayout()
+	}
+	return b.layout
 }
 
 func (b *BaseTransform) ConvertTo3D(h hex.Hex, actual hex.HexFractional) [3]float32 {
 	// ConvertToDetailed3D
 	// // glTF defines +Y as up, +Z as forward, and -X as right.
-	x, y := actual.ToCartesian()
+	p := b.Layout().HexToPixel(actual)
+	x, y := p.X, p.Y
 	z := float32(-0.5)
 
 	// fancy z
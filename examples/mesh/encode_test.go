@@ -16,11 +16,23 @@ func TestDrawArea(t *testing.T) {
 		Union(area.NewArea(hex.Hex{Q: 1, R: 2})).
 		Build()
 
-	doc, err := EncodeDetailedMesh(area, &BaseTransform{area: *area})
+	doc, err := EncodeDetailedMesh(area, NewBaseTransform(area, hex.DefaultLayout()))
 	require.NoError(t, err)
 	gltf.SaveBinary(doc, "detail_sample.glb")
 }
 
+func TestDrawAreaFlat(t *testing.T) {
+	area := area.BigHex(hex.Origin(), 3).
+		Subtract(area.BigHex(hex.Hex{Q: 2, R: 1}, 2)).
+		Build()
+
+	layout := hex.DefaultLayout()
+	layout.Orientation = hex.FlatTop
+	doc, err := EncodeDetailedMesh(area, NewBaseTransform(area, layout))
+	require.NoError(t, err)
+	require.NotEmpty(t, doc.Meshes)
+}
+
 func TestDir(t *testing.T) {
 	p := hex.Hex{Q: 2, R: -1}
 	for i := -10; i < 10; i++ {
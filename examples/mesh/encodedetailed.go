@@ -64,7 +64,7 @@ func (pc *pointCollection) addOrGetHex(h hex.Hex, t Transformer, invisible bool)
 	}
 	for i := 0; i < 6; i++ {
 		hp.points[i] = &point{
-			vert:   t.ConvertTo3D(h, hex.Center(h, h.Neighbor(i), h.Neighbor(i+1))),
+			vert:   t.ConvertTo3D(h, h.Corner(i)),
 			color:  t.PointColor(h, hex.BoundFacing(i)),
 			normal: [3]float32{0, 1, 0},
 		}
@@ -152,12 +152,11 @@ func EncodeDetailedMesh(a *area.Area, t Transformer) (doc *gltf.Document, err er
 			continue
 		}
 
-		for i, n1 := range h.h.Neighbors() {
+		for i, n1 := range h.h.NeighborArray() {
 			n2 := h.h.Neighbor(i + 1)
 			// only look at each hex triple once
-			keySlice := []hex.Hex{h.h, n1, n2}
-			sort.Sort(hex.Sort(keySlice))
-			key := [3]hex.Hex{keySlice[0], keySlice[1], keySlice[2]}
+			key := [3]hex.Hex{h.h, n1, n2}
+			sort.Sort(hex.Sort(key[:]))
 			if _, ok := seenHexTriples[key]; ok {
 				continue
 			}
@@ -233,11 +232,10 @@ func EncodeDetailedMesh(a *area.Area, t Transformer) (doc *gltf.Document, err er
 			continue
 		}
 
-		for i, n := range h.h.Neighbors() {
+		for i, n := range h.h.NeighborArray() {
 			// only look at each hex pair once
-			keySlice := []hex.Hex{h.h, n}
-			sort.Sort(hex.Sort(keySlice))
-			key := [2]hex.Hex{keySlice[0], keySlice[1]}
+			key := [2]hex.Hex{h.h, n}
+			sort.Sort(hex.Sort(key[:]))
 			if _, ok := seenHexPairs[key]; ok {
 				continue
 			}
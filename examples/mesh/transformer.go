@@ -13,6 +13,7 @@ type Transformer interface {
 	HexColor(h hex.Hex) [3]uint8
 	// PointColor sets the color for a hex point vertex that
 	// is shared by the hexes h, h.Direction(direction), and h.Direction(direction+1).
+	// This is the point at h.Corner(direction).
 	//
 	// You can use this in a fancy shader to draw borders around
 	// hexes if you make it different from HexColor().
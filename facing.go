@@ -0,0 +1,73 @@
+package hex
+
+import "math"
+
+// Facings are directions, in units of sixths of a turn.
+// Facing 0 is Direction(0), facing 1 is Direction(1), and so on.
+// Fractional facings are in between directions, so a unit
+// that is halfway through turning from direction 2 to direction 3
+// has facing 2.5.
+
+// boundFacingFractional maps any facing to [0, 6).
+func boundFacingFractional(facing float64) float64 {
+	f := math.Mod(facing, 6.0)
+	if f < 0 {
+		f += 6.0
+	}
+	return f
+}
+
+// LerpFacing finds a facing between a and b weighted by t,
+// turning whichever way is shorter.
+//
+// If a and b are exactly opposite, this turns toward
+// decreasing facings. The result is in [0, 6).
+func LerpFacing(a, b, t float64) float64 {
+	diff := boundFacingFractional(b-a+3.0) - 3.0
+	return boundFacingFractional(a + diff*t)
+}
+
+// rotateOnce rotates h about the origin by one facing.
+// This is exact, since it only swaps and negates coordinates.
+func (h HexFractional) rotateOnce() HexFractional {
+	return HexFractional{Q: -h.S(), R: -h.Q}
+}
+
+// RotateFacing moves a hex about a center point by some number of
+// facings, toward increasing facings.
+//
+// Integer facings are exact and match Hex.Rotate.
+// Fractional facings are rotated the rest of the way through
+// Cartesian space, like Rotate.
+func (h HexFractional) RotateFacing(center HexFractional, facing float64) HexFractional {
+	whole := math.Floor(facing)
+	part := facing - whole
+
+	rotated := h.Subtract(center)
+	for i := 0; i < BoundFacing(int(math.Mod(whole, 6))); i++ {
+		rotated = rotated.rotateOnce()
+	}
+	if part != 0 {
+		rotated = rotated.Rotate(HexFractional{}, part*math.Pi/3.0)
+	}
+	return rotated.Add(center)
+}
+
+// FacingToAngle returns the angle of a facing in Cartesian space,
+// in radians, as measured by math.Atan2(y, x).
+//
+// With pointy-top hexes, facing 0 is at angle 0.
+// With flat-top hexes, it is at angle π/6.
+// Increasing facings decrease the angle.
+func (o Orientation) FacingToAngle(facing float64) float64 {
+	m := o.matrix()
+	return math.Pi / 3.0 * (m.startAngle + 0.5 - facing)
+}
+
+// AngleToFacing returns the facing of an angle in Cartesian space,
+// in radians. This is the inverse of FacingToAngle.
+// The result is in [0, 6).
+func (o Orientation) AngleToFacing(radians float64) float64 {
+	m := o.matrix()
+	return boundFacingFractional(m.startAngle + 0.5 - radians*3.0/math.Pi)
+}
@@ -0,0 +1,65 @@
+package hex
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotateFacingExact(t *testing.T) {
+	center := Hex{Q: 2, R: -1}
+	for _, h := range Spiral(Hex{Q: -3, R: 4}, 3) {
+		for facing := -12; facing <= 12; facing++ {
+			rotated := h.ToHexFractional().RotateFacing(center.ToHexFractional(), float64(facing))
+			// exact, not just close.
+			require.Equal(t, h.Rotate(center, facing).ToHexFractional(), rotated, "%s by %d", h, facing)
+		}
+	}
+}
+
+func TestRotateFacingFractional(t *testing.T) {
+	center := HexFractional{Q: 0.5, R: -1.25}
+	h := HexFractional{Q: 3.5, R: 1}
+	for _, facing := range []float64{0.25, 1.5, 2.75, 5.9, -0.5, -4.1} {
+		expected := h.Rotate(center, facing*math.Pi/3.0)
+		actual := h.RotateFacing(center, facing)
+		assert.True(t, expected.AlmostEquals(actual), "facing %v: expected %s, got %s", facing, expected, actual)
+	}
+
+	// half a turn lands between two neighbors.
+	between := Direction(0).ToHexFractional().RotateFacing(HexFractional{}, 0.5)
+	assert.True(t, LerpHexFractional(Direction(0).ToHexFractional(), Direction(1).ToHexFractional(), 0.5).Normalize().AlmostEquals(between.Normalize()))
+}
+
+func TestLerpFacing(t *testing.T) {
+	assert.InDelta(t, 0.5, LerpFacing(0, 1, 0.5), 1e-9)
+	// shortest way from 5 to 1 is through 0.
+	assert.InDelta(t, 0, LerpFacing(5, 1, 0.5), 1e-9)
+	assert.InDelta(t, 5.5, LerpFacing(5, 1, 0.25), 1e-9)
+	assert.InDelta(t, 1, LerpFacing(5, 1, 1), 1e-9)
+	assert.InDelta(t, 0, LerpFacing(1, 5, 0.5), 1e-9)
+	assert.InDelta(t, 3, LerpFacing(2, 4, 0.5), 1e-9)
+	// opposite facings turn toward decreasing facings.
+	assert.InDelta(t, 5.5, LerpFacing(0, 3, 1.0/6.0), 1e-9)
+	// facings outside [0, 6) are fine.
+	assert.InDelta(t, 0.5, LerpFacing(-6, 7, 0.5), 1e-9)
+}
+
+func TestFacingAngles(t *testing.T) {
+	for _, o := range []Orientation{PointyTop, FlatTop} {
+		layout := Layout{Orientation: o, Size: Point{X: 1, Y: 1}}
+		for d := 0; d < 6; d++ {
+			p := layout.HexToPixel(Direction(d).ToHexFractional())
+			angle := math.Atan2(p.Y, p.X)
+			assert.InDelta(t, 0, math.Remainder(angle-o.FacingToAngle(float64(d)), 2*math.Pi), 1e-9, "%s direction %d", o, d)
+			assert.InDelta(t, float64(d), o.AngleToFacing(angle), 1e-9, "%s direction %d", o, d)
+		}
+		for _, facing := range []float64{0.1, 2.5, 5.99} {
+			assert.InDelta(t, facing, o.AngleToFacing(o.FacingToAngle(facing)), 1e-9)
+		}
+	}
+	assert.InDelta(t, math.Pi/6, FlatTop.FacingToAngle(0), 1e-9)
+	assert.InDelta(t, 0, PointyTop.FacingToAngle(0), 1e-9)
+}
@@ -0,0 +1,26 @@
+package fov
+
+import (
+	"github.com/erinpentecost/hex"
+	"github.com/erinpentecost/hex/area"
+)
+
+// Blocker contains domain knowledge for visibility.
+type Blocker interface {
+	// Blocks returns true if h stops line of sight.
+	// Blocking hexes can still be seen themselves.
+	Blocks(h hex.Hex) bool
+}
+
+type areaBlocker struct {
+	a *area.Area
+}
+
+func (b areaBlocker) Blocks(h hex.Hex) bool {
+	return b.a.ContainsHexes(h)
+}
+
+// BlockedBy returns a Blocker where every hex in a blocks line of sight.
+func BlockedBy(a *area.Area) Blocker {
+	return areaBlocker{a: a}
+}
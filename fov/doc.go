@@ -0,0 +1,2 @@
+// Package fov computes field of view and line of sight between hexes.
+package fov
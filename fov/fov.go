@@ -0,0 +1,119 @@
+package fov
+
+import (
+	"math"
+
+	"github.com/erinpentecost/hex"
+	"github.com/erinpentecost/hex/area"
+)
+
+// Visible returns all hexes within radius of origin that can be
+// seen from origin.
+//
+// This is shadowcasting: each ring around origin is checked against
+// the shadows cast by blocking hexes in the rings inside of it.
+// A hex is visible if the ray to its center isn't in shadow.
+// Blocking hexes are visible if any part of them isn't in shadow.
+// Rays that only graze the corner of a blocking hex aren't blocked,
+// but rays that pass between two blocking hexes are.
+//
+// origin is always visible, even if it blocks.
+// A negative radius will return an empty area.
+func Visible(origin hex.Hex, radius int64, blocker Blocker) *area.Area {
+	if radius < 0 {
+		return area.NewArea()
+	}
+
+	visible := []hex.Hex{origin}
+	s := shadows{}
+	for r := int64(1); r <= radius; r++ {
+		// shadows cast by this ring only affect the rings outside of it.
+		blocked := make([]interval, 0)
+		for _, h := range hex.Ring(origin, r) {
+			span, center := angularSpan(origin, h)
+			if blocker.Blocks(h) {
+				if !s.covers(span) {
+					visible = append(visible, h)
+				}
+				blocked = append(blocked, span)
+			} else if !s.shades(center) {
+				visible = append(visible, h)
+			}
+		}
+		s = s.add(blocked...)
+		if s.full() {
+			break
+		}
+	}
+
+	return area.NewArea(visible...)
+}
+
+// nudge is how far CanSee moves the line of sight to either side.
+const nudge = 1e-6
+
+// CanSee returns true if there is line of sight between the centers
+// of a and b.
+//
+// The line of sight is blocked if it passes through a blocking hex.
+// a and b themselves never block.
+// Lines that only graze the corner of a blocking hex aren't blocked,
+// but lines that pass between two blocking hexes are.
+//
+// CanSee is symmetric: CanSee(a, b) is always the same as CanSee(b, a).
+func CanSee(a, b hex.Hex, blocker Blocker) bool {
+	if a.DistanceTo(b) <= 1 {
+		return true
+	}
+
+	// always trace from the same end so the result is symmetric.
+	if b.Q < a.Q || (b.Q == a.Q && b.R < a.R) {
+		a, b = b, a
+	}
+
+	// find all the hexes the line might touch.
+	candidates := make(map[hex.Hex]struct{})
+	for _, h := range a.LineTo(b) {
+		candidates[h] = struct{}{}
+		for _, n := range h.NeighborArray() {
+			candidates[n] = struct{}{}
+		}
+	}
+	delete(candidates, a)
+	delete(candidates, b)
+
+	blocking := make([]hex.Hex, 0)
+	for h := range candidates {
+		if blocker.Blocks(h) {
+			blocking = append(blocking, h)
+		}
+	}
+	if len(blocking) == 0 {
+		return true
+	}
+
+	// the line is nudged to either side so it never runs exactly along an
+	// edge or through a corner. if either nudged line is clear, b can be seen.
+	ax, ay := a.ToHexFractional().ToCartesian()
+	bx, by := b.ToHexFractional().ToCartesian()
+	length := math.Hypot(bx-ax, by-ay)
+	nx, ny := -(by-ay)/length*nudge, (bx-ax)/length*nudge
+
+	for _, side := range []float64{1, -1} {
+		seg := hex.Segment{
+			A: hex.Point{X: ax + side*nx, Y: ay + side*ny},
+			B: hex.Point{X: bx + side*nx, Y: by + side*ny},
+		}
+		clear := true
+		for _, h := range blocking {
+			if seg.Crosses(h) {
+				clear = false
+				break
+			}
+		}
+		if clear {
+			return true
+		}
+	}
+	return false
+}
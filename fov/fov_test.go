@@ -0,0 +1,101 @@
+package fov_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/erinpentecost/hex"
+	"github.com/erinpentecost/hex/area"
+	"github.com/erinpentecost/hex/fov"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func randomWalls(seed int64, center hex.Hex, radius int64, density float64) *area.Area {
+	r := rand.New(rand.NewSource(seed))
+	walls := make([]hex.Hex, 0)
+	for _, h := range hex.Spiral(center, radius) {
+		if r.Float64() < density {
+			walls = append(walls, h)
+		}
+	}
+	return area.NewArea(walls...)
+}
+
+func TestVisibleOpen(t *testing.T) {
+	origin := hex.Hex{Q: 3, R: -2}
+	for radius := int64(0); radius < 8; radius++ {
+		visible := fov.Visible(origin, radius, fov.BlockedBy(area.NewArea()))
+		assert.True(t, area.BigHex(origin, radius).Equals(visible), "radius %d", radius)
+	}
+	assert.Equal(t, 0, fov.Visible(origin, -1, fov.BlockedBy(area.NewArea())).Size())
+}
+
+func TestVisibleEnclosed(t *testing.T) {
+	origin := hex.Hex{Q: -1, R: 4}
+	walls := area.Ring(origin, 3)
+	visible := fov.Visible(origin, 10, fov.BlockedBy(walls))
+	assert.True(t, area.BigHex(origin, 3).Equals(visible), "actual=%s", visible.String())
+}
+
+func TestVisibleShadow(t *testing.T) {
+	origin := hex.Origin()
+	wall := origin.Neighbor(0)
+	visible := fov.Visible(origin, 6, fov.BlockedBy(area.NewArea(wall)))
+
+	assert.True(t, visible.ContainsHexes(wall), "walls can be seen")
+	for i := int64(2); i <= 6; i++ {
+		behind := hex.Direction(0).Multiply(i)
+		assert.False(t, visible.ContainsHexes(behind), "%s is behind the wall", behind)
+	}
+	// the shadow doesn't spill onto the other side.
+	assert.True(t, visible.ContainsHexes(hex.Direction(3).Multiply(6)))
+	assert.True(t, visible.ContainsHexes(hex.DiagonalDirection(0).Multiply(3)))
+}
+
+func TestNoLeakBetweenWalls(t *testing.T) {
+	origin := hex.Origin()
+	// the ray toward diagonal 0 runs exactly along the edge
+	// shared by neighbors 0 and 1.
+	target := hex.DiagonalDirection(0)
+
+	// grazing the edge of a single wall isn't blocked.
+	graze := fov.BlockedBy(area.NewArea(origin.Neighbor(0)))
+	assert.True(t, fov.CanSee(origin, target, graze))
+	assert.True(t, fov.Visible(origin, 4, graze).ContainsHexes(target))
+
+	// passing between two walls is.
+	between := fov.BlockedBy(area.NewArea(origin.Neighbor(0), origin.Neighbor(1)))
+	assert.False(t, fov.CanSee(origin, target, between))
+	visible := fov.Visible(origin, 6, between)
+	for i := int64(1); i <= 3; i++ {
+		assert.False(t, visible.ContainsHexes(target.Multiply(i)), "%s leaked between the walls", target.Multiply(i))
+	}
+}
+
+func TestCanSeeSymmetric(t *testing.T) {
+	for seed := int64(0); seed < 5; seed++ {
+		walls := fov.BlockedBy(randomWalls(seed, hex.Origin(), 6, 0.2))
+		hexes := hex.Spiral(hex.Origin(), 6)
+		for _, a := range hexes {
+			for _, b := range hexes {
+				require.Equal(t, fov.CanSee(a, b, walls), fov.CanSee(b, a, walls), "seed %d: %s and %s", seed, a, b)
+			}
+		}
+	}
+}
+
+func TestCanSeeMatchesVisible(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		origin := hex.Hex{Q: 2, R: 1}
+		walls := randomWalls(seed, origin, 8, 0.15)
+		blocker := fov.BlockedBy(walls)
+		visible := fov.Visible(origin, 8, blocker)
+		for _, h := range hex.Spiral(origin, 8) {
+			if walls.ContainsHexes(h) {
+				continue
+			}
+			require.Equal(t, fov.CanSee(origin, h, blocker), visible.ContainsHexes(h), "seed %d: %s", seed, h)
+		}
+	}
+}
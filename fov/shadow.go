@@ -0,0 +1,109 @@
+package fov
+
+import (
+	"math"
+	"sort"
+
+	"github.com/erinpentecost/hex"
+)
+
+const (
+	tau = 2 * math.Pi
+	// epsilon is the tolerance for comparing angles.
+	epsilon = 1e-9
+)
+
+// interval is a range of angles, in radians.
+type interval struct {
+	lo float64
+	hi float64
+}
+
+// angularSpan returns the range of angles that h covers
+// when seen from the center of origin.
+// center is the angle to the center of h, and is in [0, 2π).
+func angularSpan(origin, h hex.Hex) (span interval, center float64) {
+	ox, oy := origin.ToHexFractional().ToCartesian()
+	cx, cy := h.ToHexFractional().ToCartesian()
+	center = math.Atan2(cy-oy, cx-ox)
+	if center < 0 {
+		center += tau
+	}
+
+	minOffset, maxOffset := 0.0, 0.0
+	for _, c := range h.CornerPoints() {
+		// corners are always within half a turn of the center.
+		d := math.Atan2(c.Y-oy, c.X-ox) - center
+		for d > math.Pi {
+			d -= tau
+		}
+		for d < -math.Pi {
+			d += tau
+		}
+		minOffset = math.Min(minOffset, d)
+		maxOffset = math.Max(maxOffset, d)
+	}
+	return interval{lo: center + minOffset, hi: center + maxOffset}, center
+}
+
+// shadows is a sorted list of disjoint angle intervals
+// that are blocked.
+//
+// Each interval is stored three times, offset by a full turn in
+// each direction, so intervals that cross 0 don't need special handling.
+type shadows []interval
+
+// add blocks all the given intervals.
+func (s shadows) add(blocked ...interval) shadows {
+	if len(blocked) == 0 {
+		return s
+	}
+	for _, b := range blocked {
+		for _, offset := range []float64{-tau, 0, tau} {
+			s = append(s, interval{lo: b.lo + offset, hi: b.hi + offset})
+		}
+	}
+	sort.Slice(s, func(i, j int) bool { return s[i].lo < s[j].lo })
+
+	// merge intervals that overlap or touch.
+	// touching intervals must merge, otherwise light would leak
+	// through the corner shared by two blocking hexes.
+	merged := s[:1]
+	for _, cur := range s[1:] {
+		last := &merged[len(merged)-1]
+		if cur.lo <= last.hi+epsilon {
+			last.hi = math.Max(last.hi, cur.hi)
+		} else {
+			merged = append(merged, cur)
+		}
+	}
+	return merged
+}
+
+// find returns the interval with the largest lo that is not after angle.
+func (s shadows) find(angle float64) (interval, bool) {
+	i := sort.Search(len(s), func(i int) bool { return s[i].lo > angle })
+	if i == 0 {
+		return interval{}, false
+	}
+	return s[i-1], true
+}
+
+// shades returns true if angle is strictly inside a shadow.
+// Angles that only graze the edge of a shadow are not shaded.
+func (s shadows) shades(angle float64) bool {
+	iv, ok := s.find(angle - epsilon)
+	return ok && iv.lo+epsilon < angle && angle < iv.hi-epsilon
+}
+
+// covers returns true if the whole span is inside a single shadow.
+func (s shadows) covers(span interval) bool {
+	iv, ok := s.find(span.lo + epsilon)
+	return ok && span.hi <= iv.hi+epsilon
+}
+
+// full returns true if every angle is blocked.
+func (s shadows) full() bool {
+	iv, ok := s.find(epsilon)
+	return ok && iv.hi >= tau-epsilon
+}
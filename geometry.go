@@ -0,0 +1,129 @@
+package hex
+
+import "math"
+
+// Corners and edges are numbered relative to directions:
+//
+// Corner i is shared by h, h.Neighbor(i), and h.Neighbor(i+1).
+//
+// Edge i is shared by h and h.Neighbor(i). It runs from
+// corner i-1 to corner i.
+//
+// With the default pointy-top layout, corner 0 is the upper-right corner
+// (30 degrees above +X on a y-down screen) and the corners proceed
+// counterclockwise on screen, the same way directions do.
+
+// Segment is a line segment in Cartesian space.
+type Segment struct {
+	A Point
+	B Point
+}
+
+// Corner returns corner i of the hex in hex space.
+//
+// Corner i is shared by h, h.Neighbor(i), and h.Neighbor(i+1).
+func (h Hex) Corner(corner int) HexFractional {
+	d := Direction(corner).Add(Direction(corner + 1))
+	return h.ToHexFractional().Add(d.ToHexFractional().Multiply(1.0 / 3.0))
+}
+
+// CornerPoints returns the six corners of the hex in Cartesian space,
+// using the same coordinates as HexFractional.ToCartesian.
+//
+// Corner i is shared by h, h.Neighbor(i), and h.Neighbor(i+1).
+func (h Hex) CornerPoints() [6]Point {
+	corners := [6]Point{}
+	for i := 0; i < 6; i++ {
+		x, y := h.Corner(i).ToCartesian()
+		corners[i] = Point{X: x, Y: y}
+	}
+	return corners
+}
+
+// EdgeSegments returns the six edges of the hex in Cartesian space,
+// using the same coordinates as HexFractional.ToCartesian.
+//
+// Edge i is shared by h and h.Neighbor(i). It runs from
+// corner i-1 to corner i.
+func (h Hex) EdgeSegments() [6]Segment {
+	return edgesFromCorners(h.CornerPoints())
+}
+
+// HexEdges returns the six edges of h.
+//
+// Edge i is shared by h and h.Neighbor(i). It runs from
+// corner i-1 to corner i.
+func (l Layout) HexEdges(h Hex) [6]Segment {
+	return edgesFromCorners(l.HexCorners(h))
+}
+
+func edgesFromCorners(corners [6]Point) [6]Segment {
+	edges := [6]Segment{}
+	for i := 0; i < 6; i++ {
+		edges[i] = Segment{
+			A: corners[BoundFacing(i-1)],
+			B: corners[i],
+		}
+	}
+	return edges
+}
+
+// Crosses returns true if the segment passes through the inside of h,
+// using the same coordinates as HexFractional.ToCartesian.
+// Segments that only touch the edge of h don't cross it.
+func (s Segment) Crosses(h Hex) bool {
+	return s.overlaps(h, -geometryEpsilon)
+}
+
+// Touches returns true if the segment touches any part of h,
+// including its edges and corners, using the same coordinates
+// as HexFractional.ToCartesian.
+func (s Segment) Touches(h Hex) bool {
+	return s.overlaps(h, geometryEpsilon)
+}
+
+// geometryEpsilon is the tolerance for Cartesian comparisons.
+const geometryEpsilon = 1e-9
+
+// overlaps is a separating axis test between the segment and h.
+// Projections that are within slack of each other overlap.
+func (s Segment) overlaps(h Hex, slack float64) bool {
+	normal := func(a, b Point) Point {
+		l := math.Hypot(b.X-a.X, b.Y-a.Y)
+		if l == 0 {
+			return Point{}
+		}
+		return Point{X: (a.Y - b.Y) / l, Y: (b.X - a.X) / l}
+	}
+
+	corners := h.CornerPoints()
+	// the candidate axes are the normals of the segment
+	// and the three unique hex edge normals.
+	axes := [4]Point{
+		normal(s.A, s.B),
+		normal(corners[5], corners[0]),
+		normal(corners[0], corners[1]),
+		normal(corners[1], corners[2]),
+	}
+
+	for _, axis := range axes {
+		if axis == (Point{}) {
+			continue
+		}
+		project := func(p Point) float64 {
+			return p.X*axis.X + p.Y*axis.Y
+		}
+		segMin := math.Min(project(s.A), project(s.B))
+		segMax := math.Max(project(s.A), project(s.B))
+		hexMin, hexMax := math.Inf(1), math.Inf(-1)
+		for _, c := range corners {
+			p := project(c)
+			hexMin = math.Min(hexMin, p)
+			hexMax = math.Max(hexMax, p)
+		}
+		if segMax+slack < hexMin || hexMax+slack < segMin {
+			return false
+		}
+	}
+	return true
+}
@@ -3,6 +3,7 @@ package hex
 import (
 	"fmt"
 	"math"
+	"sort"
 
 	"github.com/erinpentecost/hex/internal"
 )
@@ -125,6 +126,98 @@ func (h Hex) LineTo(x Hex) []Hex {
 	return line
 }
 
+// lineNudge is added to both ends of a nudged line so
+// that it never lands exactly on the boundary between hexes.
+var lineNudge = HexFractional{Q: 1e-6, R: 2e-6}
+
+// lineOrdered returns true if lines from h to x should be traced
+// from h. Lines are always traced from the same end
+// so they are symmetric.
+func lineOrdered(h, x Hex) bool {
+	return h.Q < x.Q || (h.Q == x.Q && h.R <= x.R)
+}
+
+func reverseHexes(hexes []Hex) []Hex {
+	for i, j := 0, len(hexes)-1; i < j; i, j = i+1, j-1 {
+		hexes[i], hexes[j] = hexes[j], hexes[i]
+	}
+	return hexes
+}
+
+// NudgedLineTo returns all hexes in a line from point h to point x, inclusive.
+//
+// Unlike LineTo, the line is moved a tiny bit so that it never
+// runs exactly along the boundary between two hexes. This makes it
+// deterministic and symmetric: the line from x to h is exactly
+// this line in reverse.
+func (h Hex) NudgedLineTo(x Hex) []Hex {
+	if !lineOrdered(h, x) {
+		return reverseHexes(x.NudgedLineTo(h))
+	}
+
+	n := h.DistanceTo(x)
+	line := make([]Hex, 0, n+1)
+	a := h.ToHexFractional().Add(lineNudge)
+	b := x.ToHexFractional().Add(lineNudge)
+	step := 1.0 / math.Max(float64(n), 1.0)
+	for i := int64(0); i <= n; i++ {
+		line = append(line, LerpHexFractional(a, b, step*float64(i)).ToHex())
+	}
+	return line
+}
+
+// SupercoverLineTo returns every hex that the line segment between
+// the centers of h and x touches, including hexes that it only
+// touches on an edge or corner.
+//
+// Hexes are ordered by how far along the line they are.
+// The line from x to h is exactly this line in reverse.
+func (h Hex) SupercoverLineTo(x Hex) []Hex {
+	if !lineOrdered(h, x) {
+		return reverseHexes(x.SupercoverLineTo(h))
+	}
+
+	ax, ay := h.ToHexFractional().ToCartesian()
+	bx, by := x.ToHexFractional().ToCartesian()
+	seg := Segment{A: Point{X: ax, Y: ay}, B: Point{X: bx, Y: by}}
+
+	// every hex the segment touches is next to a hex on the line.
+	touched := make(map[Hex]struct{})
+	line := make([]Hex, 0)
+	for _, l := range h.LineTo(x) {
+		for i := 0; i <= 6; i++ {
+			c := l
+			if i < 6 {
+				c = l.Neighbor(i)
+			}
+			if _, ok := touched[c]; ok {
+				continue
+			}
+			if seg.Touches(c) {
+				touched[c] = struct{}{}
+				line = append(line, c)
+			}
+		}
+	}
+
+	// order by distance along the line, then by which side of the line
+	// the hex is on.
+	dx, dy := bx-ax, by-ay
+	along := func(k Hex) (float64, float64) {
+		kx, ky := k.ToHexFractional().ToCartesian()
+		return (kx-ax)*dx + (ky-ay)*dy, (kx-ax)*dy - (ky-ay)*dx
+	}
+	sort.Slice(line, func(i, j int) bool {
+		ti, si := along(line[i])
+		tj, sj := along(line[j])
+		if math.Abs(ti-tj) > geometryEpsilon {
+			return ti < tj
+		}
+		return si < sj
+	})
+	return line
+}
+
 // LerpHex finds a point between a and b weighted by t.
 // See https://en.wikipedia.org/wiki/Linear_interpolation
 func LerpHex(a Hex, b Hex, t float64) Hex {
@@ -143,6 +236,20 @@ func absInt(k int64) int64 {
 	return -1 * k
 }
 
+func maxInt(a, k int64) int64 {
+	if a > k {
+		return a
+	}
+	return k
+}
+
+func minInt(a, k int64) int64 {
+	if a < k {
+		return a
+	}
+	return k
+}
+
 // Length gets the length of the hex to the grid origin.
 //
 // This is the Manhattan Distance.
@@ -176,39 +283,138 @@ func (h Hex) Neighbor(direction int) Hex {
 	return h.Add(d)
 }
 
-// Neighbors returns the neighbors.
+// Neighbors returns the six neighbors in direction order.
+//
+// Use NeighborArray or ForEachNeighbor to avoid allocating.
 func (h Hex) Neighbors() []Hex {
-	n := make([]Hex, 7)
-	for i := 0; i <= 6; i++ {
+	n := make([]Hex, 6)
+	for i := 0; i < 6; i++ {
+		n[i] = h.Neighbor(i)
+	}
+	return n
+}
+
+// NeighborArray returns the six neighbors in direction order,
+// without allocating.
+func (h Hex) NeighborArray() [6]Hex {
+	n := [6]Hex{}
+	for i := 0; i < 6; i++ {
 		n[i] = h.Neighbor(i)
 	}
 	return n
 }
 
-// Transform applies a matrix transformation on the hex.
+// ForEachNeighbor calls fn with each neighbor in direction order,
+// without allocating. It stops early if fn returns false.
+func (h Hex) ForEachNeighbor(fn func(direction int, neighbor Hex) bool) {
+	for i := 0; i < 6; i++ {
+		if !fn(i, h.Neighbor(i)) {
+			return
+		}
+	}
+}
+
+// DiagonalDirection returns a new hex coord offset from the origin
+// in the given diagonal direction, which is a number from 0 to 5, inclusive.
 //
-// Translation by tr,tq,ts:
+// Diagonal i sits between Direction(i) and Direction(i+1), straight out
+// from corner i. Diagonal neighbors don't touch the hex; they are on the
+// other side of the edge shared by h.Neighbor(i) and h.Neighbor(i+1).
+func DiagonalDirection(direction int) Hex {
+	return Direction(direction).Add(Direction(direction + 1))
+}
+
+// DiagonalNeighbor returns the diagonal neighbor in the given direction.
+func (h Hex) DiagonalNeighbor(direction int) Hex {
+	return h.Add(DiagonalDirection(direction))
+}
+
+// DiagonalNeighbors returns the six diagonal neighbors.
+func (h Hex) DiagonalNeighbors() []Hex {
+	n := make([]Hex, 6)
+	for i := 0; i < 6; i++ {
+		n[i] = h.DiagonalNeighbor(i)
+	}
+	return n
+}
+
+// DiagonalLength gets the length of the hex to the grid origin
+// when diagonal steps are allowed.
 //
-// [[1,0,0,tr]
+// This is the fewest number of steps to a neighbor or
+// diagonal neighbor it takes to get there.
+func (h Hex) DiagonalLength() int64 {
+	// sort the absolute cube coordinates, largest first.
+	a, b, c := absInt(h.Q), absInt(h.R), absInt(h.S())
+	if a < b {
+		a, b = b, a
+	}
+	if a < c {
+		a, c = c, a
+	}
+	if b < c {
+		b = c
+	}
+	// a diagonal step moves the largest coordinate by 2, but two diagonal
+	// steps can only cover 3 along a straight neighbor line.
+	return maxInt((a+1)/2, (a+b+2)/3)
+}
+
+// DiagonalDistanceTo returns the distance between two hexes
+// when diagonal steps are allowed.
+func (h Hex) DiagonalDistanceTo(x Hex) int64 {
+	return h.Subtract(x).DiagonalLength()
+}
+
+// Ring returns the hexes that are exactly radius away from center.
 //
-// [0,1,0,tq]
+// The ring starts at center.Add(Direction(4).Multiply(radius)) and walks
+// radius steps in each direction from 0 to 5, so each hex is a neighbor
+// of the one before it. On a y-down screen this is counterclockwise.
+// A radius of 0 will return the center hex.
+// A negative radius will return no hexes.
+func Ring(center Hex, radius int64) []Hex {
+	if radius < 0 {
+		return []Hex{}
+	}
+	if radius == 0 {
+		return []Hex{center}
+	}
+
+	ring := make([]Hex, 0, 6*radius)
+	h := center.Add(Direction(4).Multiply(radius))
+	for i := 0; i < 6; i++ {
+		for j := int64(0); j < radius; j++ {
+			ring = append(ring, h)
+			h = h.Neighbor(i)
+		}
+	}
+	return ring
+}
+
+// Spiral returns all hexes within radius of center.
 //
-// [0,0,1,ts]
+// The spiral starts with center, then contains each Ring from
+// radius 1 outward, in Ring order.
+// A negative radius will return no hexes.
+func Spiral(center Hex, radius int64) []Hex {
+	if radius < 0 {
+		return []Hex{}
+	}
+
+	spiral := make([]Hex, 0, 1+3*radius*(radius+1))
+	for r := int64(0); r <= radius; r++ {
+		spiral = append(spiral, Ring(center, r)...)
+	}
+	return spiral
+}
+
+// Transform applies t to the hex. It is the same as t.Apply(h).
 //
-// [0,0,0,1]] // homogenous coords. ignored.
-func (h Hex) Transform(t [4][4]int64) Hex {
-	p := Hex{
-		Q: t[0][0]*h.Q + t[0][1]*h.R + t[0][2]*h.S() + t[0][3],
-		R: t[1][0]*h.Q + t[1][1]*h.R + t[1][2]*h.S() + t[1][3],
-	}
-	/*
-		// No need to transform S since it's a derived field.
-		s := t[2][0]*h.Q + t[2][1]*h.R + t[2][2]*h.S() + t[2][3]
-		if p.S() != s {
-			panic("transformation matrix is bad")
-		}
-	*/
-	return p
+// Make transforms with TranslateTransform, RotateTransform and
+// ReflectTransform, and chain them with Transform.Compose.
+func (h Hex) Transform(t Transform) Hex {
+	return t.Apply(h)
 }
 
 func (h Hex) Rotate(pivot Hex, direction int) Hex {
@@ -224,6 +430,52 @@ func (h Hex) Rotate(pivot Hex, direction int) Hex {
 	return h.Subtract(pivot).Transform(internal.RotationMatrixes[d]).Add(pivot)
 }
 
+// Axis is a line through the origin that hexes can be reflected across.
+type Axis byte
+
+const (
+	// AxisQ keeps Q and swaps R and S.
+	AxisQ Axis = iota
+	// AxisR keeps R and swaps Q and S.
+	AxisR
+	// AxisS keeps S and swaps Q and R.
+	AxisS
+	// AxisQR is halfway between AxisQ and AxisR.
+	// It keeps the hexes where S is 0.
+	AxisQR
+	// AxisRS is halfway between AxisR and AxisS.
+	// It keeps the hexes where Q is 0.
+	AxisRS
+	// AxisSQ is halfway between AxisS and AxisQ.
+	// It keeps the hexes where R is 0.
+	AxisSQ
+)
+
+func (a Axis) String() string {
+	switch a {
+	case AxisQ:
+		return "q"
+	case AxisR:
+		return "r"
+	case AxisS:
+		return "s"
+	case AxisQR:
+		return "qr"
+	case AxisRS:
+		return "rs"
+	case AxisSQ:
+		return "sq"
+	default:
+		return "?"
+	}
+}
+
+// Reflect mirrors the hex across the given axis,
+// which passes through the origin.
+func (h Hex) Reflect(axis Axis) Hex {
+	return h.Transform(internal.ReflectMatrix(int(axis)))
+}
+
 // BoundFacing maps the whole number set to 0-5.
 func BoundFacing(facing int) int {
 	return internal.BoundFacing(facing)
@@ -177,6 +177,9 @@ func init() {
 }
 
 // ToCartesian returns the hex in Cartesian Coordinates.
+//
+// This uses pointy-top, unit-sized hexes centered on 0,0.
+// Use a Layout for anything else.
 func (h HexFractional) ToCartesian() (x, y float64) {
 	x = sqrt3*h.Q + sqrt3*h.R/2.0
 	y = 1.5 * h.R
@@ -184,6 +187,8 @@ func (h HexFractional) ToCartesian() (x, y float64) {
 }
 
 // HexFractionalFromCartesian returns the hex in Cartesian Coordinates.
+//
+// This is the inverse of ToCartesian.
 func HexFractionalFromCartesian(x, y float64) HexFractional {
 	// rotate y by 30 degrees to get R
 	return HexFractional{
@@ -5,22 +5,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-func maxInt(a, k int64) int64 {
-	if a > k {
-		return a
-	}
-	return k
-}
-
-func minInt(a, k int64) int64 {
-	if a < k {
-		return a
-	}
-	return k
-}
-
 // HexArea returns the set of hexes that form a larger hex area
 // centered around the starting hex and with the given radius.
 // The order of elements returned is not set.
@@ -161,3 +148,224 @@ func TestLine(t *testing.T) {
 		})
 	}
 }
+
+func TestRing(t *testing.T) {
+	center := Hex{Q: 4, R: -9}
+	assert.Empty(t, Ring(center, -1))
+	assert.Equal(t, []Hex{center}, Ring(center, 0))
+
+	for radius := int64(1); radius < 8; radius++ {
+		ring := Ring(center, radius)
+		require.Len(t, ring, int(6*radius))
+		assert.Equal(t, center.Add(Direction(4).Multiply(radius)), ring[0])
+
+		seen := make(map[Hex]struct{})
+		for i, h := range ring {
+			seen[h] = struct{}{}
+			require.Equal(t, radius, center.DistanceTo(h), "hex %d of ring %d", i, radius)
+			next := ring[(i+1)%len(ring)]
+			require.EqualValues(t, 1, h.DistanceTo(next), "hex %d of ring %d", i, radius)
+		}
+		require.Len(t, seen, len(ring))
+	}
+}
+
+func TestSpiral(t *testing.T) {
+	center := Hex{Q: -1, R: 2}
+	assert.Empty(t, Spiral(center, -1))
+
+	for radius := int64(0); radius < 8; radius++ {
+		spiral := Spiral(center, radius)
+		require.Len(t, spiral, len(HexArea(Origin(), radius)))
+		assert.Equal(t, center, spiral[0])
+
+		seen := make(map[Hex]struct{})
+		last := int64(0)
+		for _, h := range spiral {
+			seen[h] = struct{}{}
+			d := center.DistanceTo(h)
+			require.LessOrEqual(t, d, radius)
+			require.GreaterOrEqual(t, d, last, "spiral must not move inward")
+			last = d
+		}
+		require.Len(t, seen, len(spiral))
+	}
+}
+
+var allAxes = []Axis{AxisQ, AxisR, AxisS, AxisQR, AxisRS, AxisSQ}
+
+func TestReflect(t *testing.T) {
+	testHexes := HexArea(Origin(), 6)
+	fixed := map[Axis]func(h Hex) bool{
+		AxisQ:  func(h Hex) bool { return h.R == h.S() },
+		AxisR:  func(h Hex) bool { return h.Q == h.S() },
+		AxisS:  func(h Hex) bool { return h.Q == h.R },
+		AxisQR: func(h Hex) bool { return h.S() == 0 },
+		AxisRS: func(h Hex) bool { return h.Q == 0 },
+		AxisSQ: func(h Hex) bool { return h.R == 0 },
+	}
+	for _, axis := range allAxes {
+		t.Run(axis.String(), func(t *testing.T) {
+			for _, h := range testHexes {
+				m := h.Reflect(axis)
+				require.Equal(t, h, m.Reflect(axis), "reflecting twice should be the identity")
+				require.Equal(t, h.Length(), m.Length())
+				require.Equal(t, fixed[axis](h), h == m, "%s reflected to %s", h, m)
+				for i := 0; i < 6; i++ {
+					require.EqualValues(t, 1, m.DistanceTo(h.Neighbor(i).Reflect(axis)))
+				}
+			}
+		})
+	}
+}
+
+func TestReflectComposesToRotation(t *testing.T) {
+	testHexes := HexArea(Origin(), 3)
+	for _, a := range allAxes {
+		for _, b := range allAxes {
+			found := false
+			for d := 0; d < 6 && !found; d++ {
+				match := true
+				for _, h := range testHexes {
+					if h.Reflect(a).Reflect(b) != h.Rotate(Origin(), d) {
+						match = false
+						break
+					}
+				}
+				found = match
+			}
+			assert.True(t, found, "reflecting across %s then %s is not a rotation", a, b)
+		}
+	}
+}
+
+func TestDiagonalNeighbors(t *testing.T) {
+	h := Hex{Q: 3, R: -8}
+	for i, d := range h.DiagonalNeighbors() {
+		assert.Equal(t, h.DiagonalNeighbor(i), d)
+		assert.Equal(t, h, d.DiagonalNeighbor(i+3))
+		assert.EqualValues(t, 2, h.DistanceTo(d))
+		// diagonal neighbors are straight out from a corner.
+		assert.True(t, h.ToHexFractional().Add(h.Corner(i).Subtract(h.ToHexFractional()).Multiply(3)).AlmostEquals(d.ToHexFractional()))
+		assert.EqualValues(t, 1, d.DistanceTo(h.Neighbor(i)))
+		assert.EqualValues(t, 1, d.DistanceTo(h.Neighbor(i+1)))
+		assert.EqualValues(t, 1, h.DiagonalDistanceTo(d))
+	}
+}
+
+func TestDiagonalDistance(t *testing.T) {
+	// breadth-first search with neighbor and diagonal steps.
+	radius := int64(12)
+	dist := map[Hex]int64{Origin(): 0}
+	queue := []Hex{Origin()}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for i := 0; i < 6; i++ {
+			for _, next := range []Hex{cur.Neighbor(i), cur.DiagonalNeighbor(i)} {
+				if _, ok := dist[next]; ok || next.Length() > radius {
+					continue
+				}
+				dist[next] = dist[cur] + 1
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	for h, d := range dist {
+		// stay away from the edge, where the search is clipped.
+		if h.Length() > radius-3 {
+			continue
+		}
+		require.Equal(t, d, h.DiagonalLength(), "diagonal length of %s", h)
+		require.Equal(t, d, h.Add(Hex{Q: 7, R: 2}).DiagonalDistanceTo(Hex{Q: 7, R: 2}))
+	}
+}
+
+func isConnected(line []Hex) bool {
+	for i := 1; i < len(line); i++ {
+		if line[i-1].DistanceTo(line[i]) != 1 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestNudgedLine(t *testing.T) {
+	for _, a := range Spiral(Hex{Q: 1, R: -2}, 3) {
+		for _, b := range Spiral(Hex{Q: -2, R: 3}, 4) {
+			line := a.NudgedLineTo(b)
+			require.Len(t, line, int(a.DistanceTo(b))+1)
+			require.Equal(t, a, line[0])
+			require.Equal(t, b, line[len(line)-1])
+			require.True(t, isConnected(line), "%s to %s", a, b)
+
+			back := b.NudgedLineTo(a)
+			require.Equal(t, line, reverseHexes(back), "%s to %s", a, b)
+		}
+	}
+}
+
+func TestSupercoverLine(t *testing.T) {
+	for _, a := range Spiral(Hex{Q: 1, R: -2}, 2) {
+		for _, b := range Spiral(Hex{Q: -2, R: 3}, 4) {
+			line := a.SupercoverLineTo(b)
+			require.Equal(t, a, line[0])
+			require.Equal(t, b, line[len(line)-1])
+			require.True(t, isConnected(line), "%s to %s", a, b)
+
+			back := b.SupercoverLineTo(a)
+			require.Equal(t, line, reverseHexes(back), "%s to %s", a, b)
+
+			// covers the other kinds of line.
+			require.Subset(t, line, a.LineTo(b))
+			require.Subset(t, line, a.NudgedLineTo(b))
+		}
+	}
+}
+
+func TestSupercoverLineAlongEdge(t *testing.T) {
+	// this line runs exactly along the edge between
+	// neighbors 0 and 1.
+	line := Origin().SupercoverLineTo(DiagonalDirection(0))
+	assert.ElementsMatch(t, []Hex{Origin(), Direction(0), Direction(1), DiagonalDirection(0)}, line)
+	assert.Equal(t, Origin(), line[0])
+	assert.Equal(t, DiagonalDirection(0), line[3])
+
+	// lerped lines pick one side.
+	assert.Len(t, Origin().LineTo(DiagonalDirection(0)), 3)
+	assert.Len(t, Origin().NudgedLineTo(DiagonalDirection(0)), 3)
+}
+
+func TestNeighborTraversal(t *testing.T) {
+	h := Hex{Q: 4, R: -9}
+	arr := h.NeighborArray()
+
+	visited := make([]Hex, 0, 6)
+	h.ForEachNeighbor(func(direction int, neighbor Hex) bool {
+		assert.Equal(t, h.Neighbor(direction), neighbor)
+		visited = append(visited, neighbor)
+		return true
+	})
+	assert.Equal(t, arr[:], visited)
+
+	// early exit
+	count := 0
+	h.ForEachNeighbor(func(direction int, neighbor Hex) bool {
+		count++
+		return direction < 2
+	})
+	assert.Equal(t, 3, count)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		total := int64(0)
+		for _, n := range h.NeighborArray() {
+			total += n.Q
+		}
+		h.ForEachNeighbor(func(direction int, neighbor Hex) bool {
+			total += neighbor.R
+			return true
+		})
+	})
+	assert.Zero(t, allocs)
+}
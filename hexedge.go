@@ -0,0 +1,152 @@
+package hex
+
+import "fmt"
+
+// HexEdge is the edge shared by two neighboring hexes.
+//
+// Each edge has exactly one HexEdge value, so HexEdges can be compared
+// with == and used as map keys. Use Hex.Edge to create one.
+type HexEdge struct {
+	// h owns the edge.
+	h Hex
+	// d is the direction from h to the other hex, from 0 to 2 inclusive.
+	d int8
+}
+
+// HexVertex is the corner shared by three hexes.
+//
+// Each corner has exactly one HexVertex value, so HexVertexes can be
+// compared with == and used as map keys. Use Hex.Vertex to create one.
+type HexVertex struct {
+	// h owns the vertex.
+	h Hex
+	// c is the corner of h, either 0 or 1.
+	c int8
+}
+
+// Edge returns the edge shared by h and h.Neighbor(direction).
+func (h Hex) Edge(direction int) HexEdge {
+	d := BoundFacing(direction)
+	if d > 2 {
+		return HexEdge{h: h.Neighbor(d), d: int8(d - 3)}
+	}
+	return HexEdge{h: h, d: int8(d)}
+}
+
+// Edges returns the six edges of h.
+// Edge i is shared by h and h.Neighbor(i).
+func (h Hex) Edges() [6]HexEdge {
+	edges := [6]HexEdge{}
+	for i := 0; i < 6; i++ {
+		edges[i] = h.Edge(i)
+	}
+	return edges
+}
+
+// Vertex returns corner i of h, which is shared by
+// h, h.Neighbor(i), and h.Neighbor(i+1).
+func (h Hex) Vertex(corner int) HexVertex {
+	// only corners 0 and 1 are owned by a hex.
+	// the other four belong to neighbors.
+	switch BoundFacing(corner) {
+	case 0:
+		return HexVertex{h: h, c: 0}
+	case 1:
+		return HexVertex{h: h, c: 1}
+	case 2:
+		return HexVertex{h: h.Neighbor(3), c: 0}
+	case 3:
+		return HexVertex{h: h.Neighbor(4), c: 1}
+	case 4:
+		return HexVertex{h: h.Neighbor(4), c: 0}
+	case 5:
+		return HexVertex{h: h.Neighbor(5), c: 1}
+	}
+	panic("should never get here.")
+}
+
+// Vertices returns the six corners of h.
+// Corner i is shared by h, h.Neighbor(i), and h.Neighbor(i+1).
+func (h Hex) Vertices() [6]HexVertex {
+	vertices := [6]HexVertex{}
+	for i := 0; i < 6; i++ {
+		vertices[i] = h.Vertex(i)
+	}
+	return vertices
+}
+
+// Hexes returns the two hexes that share the edge.
+func (e HexEdge) Hexes() [2]Hex {
+	return [2]Hex{e.h, e.h.Neighbor(int(e.d))}
+}
+
+// Vertices returns the two endpoints of the edge.
+func (e HexEdge) Vertices() [2]HexVertex {
+	return [2]HexVertex{e.h.Vertex(int(e.d) - 1), e.h.Vertex(int(e.d))}
+}
+
+// AdjacentEdges returns the four edges that share an endpoint with e.
+func (e HexEdge) AdjacentEdges() [4]HexEdge {
+	adjacent := [4]HexEdge{}
+	i := 0
+	for _, v := range e.Vertices() {
+		for _, o := range v.Edges() {
+			if o != e {
+				adjacent[i] = o
+				i++
+			}
+		}
+	}
+	return adjacent
+}
+
+// Midpoint returns the center of the edge in hex space.
+func (e HexEdge) Midpoint() HexFractional {
+	return LerpHexFractional(e.h.ToHexFractional(), e.h.Neighbor(int(e.d)).ToHexFractional(), 0.5)
+}
+
+// String converts the edge to a string.
+func (e HexEdge) String() string {
+	h := e.Hexes()
+	return fmt.Sprintf("%s|%s", h[0].String(), h[1].String())
+}
+
+// Hexes returns the three hexes that share the vertex.
+func (v HexVertex) Hexes() [3]Hex {
+	return [3]Hex{v.h, v.h.Neighbor(int(v.c)), v.h.Neighbor(int(v.c) + 1)}
+}
+
+// Edges returns the three edges that meet at the vertex.
+func (v HexVertex) Edges() [3]HexEdge {
+	c := int(v.c)
+	return [3]HexEdge{
+		v.h.Edge(c),
+		v.h.Edge(c + 1),
+		v.h.Neighbor(c).Edge(c + 2),
+	}
+}
+
+// AdjacentVertices returns the three vertices that are one edge away from v.
+func (v HexVertex) AdjacentVertices() [3]HexVertex {
+	adjacent := [3]HexVertex{}
+	for i, e := range v.Edges() {
+		ends := e.Vertices()
+		if ends[0] == v {
+			adjacent[i] = ends[1]
+		} else {
+			adjacent[i] = ends[0]
+		}
+	}
+	return adjacent
+}
+
+// ToHexFractional returns the position of the vertex in hex space.
+func (v HexVertex) ToHexFractional() HexFractional {
+	return v.h.Corner(int(v.c))
+}
+
+// String converts the vertex to a string.
+func (v HexVertex) String() string {
+	h := v.Hexes()
+	return fmt.Sprintf("%s|%s|%s", h[0].String(), h[1].String(), h[2].String())
+}
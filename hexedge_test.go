@@ -0,0 +1,67 @@
+package hex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEdgeCanonical(t *testing.T) {
+	edges := make(map[HexEdge]struct{})
+	for _, h := range HexArea(Origin(), 6) {
+		for i := -6; i < 12; i++ {
+			e := h.Edge(i)
+			require.Equal(t, e, h.Neighbor(i).Edge(i+3), "edge %d of %s", i, h)
+			hexes := e.Hexes()
+			require.ElementsMatch(t, []Hex{h, h.Neighbor(i)}, hexes[:])
+			require.True(t, e.Midpoint().AlmostEquals(LerpHexFractional(h.ToHexFractional(), h.Neighbor(i).ToHexFractional(), 0.5)))
+			edges[e] = struct{}{}
+		}
+	}
+	// interior edges are shared by two hexes, boundary edges are not.
+	hexCount := len(HexArea(Origin(), 6))
+	boundaryCount := 6 * (2*6 + 1)
+	assert.Equal(t, (6*hexCount+boundaryCount)/2, len(edges))
+}
+
+func TestVertexCanonical(t *testing.T) {
+	for _, h := range HexArea(Origin(), 6) {
+		for i := 0; i < 6; i++ {
+			v := h.Vertex(i)
+			hexes := v.Hexes()
+			require.ElementsMatch(t, []Hex{h, h.Neighbor(i), h.Neighbor(i + 1)}, hexes[:], "corner %d of %s", i, h)
+			require.Equal(t, v, h.Vertex(i+6))
+			require.True(t, v.ToHexFractional().AlmostEquals(h.Corner(i)))
+
+			// the neighbors see the same vertex.
+			require.Equal(t, v, h.Neighbor(i).Vertex(i+2), "corner %d of %s", i, h)
+			require.Equal(t, v, h.Neighbor(i+1).Vertex(i+4), "corner %d of %s", i, h)
+		}
+	}
+}
+
+func TestVertexEdgeAdjacency(t *testing.T) {
+	for _, h := range HexArea(Origin(), 3) {
+		for i := 0; i < 6; i++ {
+			v := h.Vertex(i)
+			for _, e := range v.Edges() {
+				require.Contains(t, e.Vertices(), v)
+			}
+			for _, o := range v.AdjacentVertices() {
+				require.NotEqual(t, v, o)
+				require.InDelta(t, 1.0/3.0, v.ToHexFractional().DistanceTo(o.ToHexFractional())*v.ToHexFractional().DistanceTo(o.ToHexFractional()), 1e-9)
+			}
+
+			e := h.Edge(i)
+			ends := e.Vertices()
+			require.ElementsMatch(t, []HexVertex{h.Vertex(i - 1), h.Vertex(i)}, ends[:])
+			seen := make(map[HexEdge]struct{})
+			for _, o := range e.AdjacentEdges() {
+				require.NotEqual(t, e, o)
+				seen[o] = struct{}{}
+			}
+			require.Len(t, seen, 4)
+		}
+	}
+}
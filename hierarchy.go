@@ -0,0 +1,90 @@
+package hex
+
+import "math"
+
+// Hexes can be grouped into a hierarchy of coarser and coarser grids.
+//
+// Each parent hex covers exactly seven child hexes: the child at its
+// center, and that child's six neighbors. Parents are themselves hexes
+// in a grid one level up, and can be grouped again.
+//
+// A parent grid is not lined up with its child grid. Each level up
+// is scaled by √7 and rotated by atan(√3/5) (about 19.1°) toward
+// decreasing facings, so LevelRotation(1) is about -0.333 radians.
+// The rotation adds up: n levels up is rotated by n times as much.
+
+// levelRotation is the angle, in radians, between one level and
+// the one below it.
+var levelRotation = -math.Atan(math.Sqrt(3) / 5)
+
+// LevelRotation returns the angle, in radians, between a grid and
+// the grid levels above it. Positive angles are toward
+// increasing facings, like HexFractional.Rotate.
+func LevelRotation(levels int) float64 {
+	return float64(levels) * levelRotation
+}
+
+// LevelScale returns how many times farther apart the hexes in a grid
+// levels above are, compared to the original grid.
+func LevelScale(levels int) float64 {
+	return math.Pow(math.Sqrt(7), float64(levels))
+}
+
+// CenterChild returns the child hex at the center of h,
+// one level down.
+func (h Hex) CenterChild() Hex {
+	return h.Multiply(2).Add(h.Rotate(Origin(), 5))
+}
+
+// Children returns the seven hexes one level down that make up h.
+// The center child is first, followed by its neighbors in
+// direction order.
+func (h Hex) Children() [7]Hex {
+	center := h.CenterChild()
+	children := [7]Hex{center}
+	for i := 0; i < 6; i++ {
+		children[i+1] = center.Neighbor(i)
+	}
+	return children
+}
+
+// Parent returns the hex one level up that h is a child of.
+func (h Hex) Parent() Hex {
+	// undo CenterChild, rounding to the nearest hex. The rounded
+	// guess is never more than one step from the parent.
+	guess := Hex{
+		Q: floorDiv(3*h.Q+h.R+3, 7),
+		R: floorDiv(2*h.R-h.Q+3, 7),
+	}
+	if guess.CenterChild().DistanceTo(h) > 1 {
+		for i := 0; i < 6; i++ {
+			if p := guess.Neighbor(i); p.CenterChild().DistanceTo(h) <= 1 {
+				return p
+			}
+		}
+	}
+	return guess
+}
+
+// Ancestor returns the hex levels up that h is a descendant of.
+func (h Hex) Ancestor(levels int) Hex {
+	for i := 0; i < levels; i++ {
+		h = h.Parent()
+	}
+	return h
+}
+
+// Descendants returns the hexes levels down that make up h.
+// There are 7^levels of them.
+func (h Hex) Descendants(levels int) []Hex {
+	hexes := []Hex{h}
+	for i := 0; i < levels; i++ {
+		next := make([]Hex, 0, len(hexes)*7)
+		for _, k := range hexes {
+			children := k.Children()
+			next = append(next, children[:]...)
+		}
+		hexes = next
+	}
+	return hexes
+}
@@ -0,0 +1,85 @@
+package hex
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChildrenAndParent(t *testing.T) {
+	seen := make(map[Hex]Hex)
+	for _, p := range Spiral(Hex{Q: -2, R: 5}, 6) {
+		children := p.Children()
+		require.Equal(t, p.CenterChild(), children[0])
+		for _, c := range children {
+			require.Equal(t, p, c.Parent(), "child %s of %s", c, p)
+			// children don't overlap.
+			other, ok := seen[c]
+			require.False(t, ok, "%s is a child of %s and %s", c, p, other)
+			seen[c] = p
+		}
+	}
+
+	// every hex has a parent.
+	for _, h := range Spiral(Hex{Q: 100, R: -37}, 10) {
+		p := h.Parent()
+		children := p.Children()
+		assert.Contains(t, children[:], h)
+	}
+}
+
+func TestDescendants(t *testing.T) {
+	h := Hex{Q: 3, R: -1}
+	for levels := 0; levels < 4; levels++ {
+		descendants := h.Descendants(levels)
+		require.Len(t, descendants, int(math.Pow(7, float64(levels))))
+		unique := make(map[Hex]bool)
+		for _, d := range descendants {
+			require.Equal(t, h, d.Ancestor(levels))
+			unique[d] = true
+		}
+		require.Len(t, unique, len(descendants))
+	}
+}
+
+func TestLevelRotation(t *testing.T) {
+	// the center child of a neighbor is rotated and scaled
+	// the same way as the grid.
+	for levels := 1; levels < 4; levels++ {
+		c := Direction(0)
+		for i := 0; i < levels; i++ {
+			c = c.CenterChild()
+		}
+		x, y := c.ToHexFractional().ToCartesian()
+		ox, oy := Direction(0).ToHexFractional().ToCartesian()
+		assert.InDelta(t, LevelScale(levels), math.Hypot(x, y)/math.Hypot(ox, oy), 1e-9)
+
+		expected := Direction(0).ToHexFractional().Rotate(Origin().ToHexFractional(), LevelRotation(levels)).Multiply(LevelScale(levels))
+		assert.InDelta(t, expected.Q, float64(c.Q), 1e-9)
+		assert.InDelta(t, expected.R, float64(c.R), 1e-9)
+	}
+}
+
+func TestParentFarAway(t *testing.T) {
+	for _, h := range []Hex{
+		{Q: 1 << 60, R: 3},
+		{Q: 1<<55 + 3, R: -(1 << 54) + 1},
+		{Q: -(1 << 60), R: 1<<60 - 5},
+		{Q: 1<<60 - 1, R: -(1 << 60)},
+		{Q: -(1 << 59) + 7, R: -(1 << 60)},
+	} {
+		for _, k := range Spiral(h, 3) {
+			p := k.Parent()
+			children := p.Children()
+			assert.Contains(t, children[:], k)
+		}
+
+		// children of a parent out there find their way back.
+		p := Hex{Q: h.Q / 3, R: h.R / 3}
+		for _, c := range p.Children() {
+			require.Equal(t, p, c.Parent(), "child %s of %s", c, p)
+		}
+	}
+}
@@ -9,6 +9,14 @@ var (
 		{{0, 0, 1, 0}, {1, 0, 0, 0}, {0, 1, 0, 0}, {0, 0, 0, 1}},
 		{{0, -1, 0, 0}, {0, 0, -1, 0}, {-1, 0, 0, 0}, {0, 0, 0, 1}},
 	}
+	ReflectionMatrixes = [6][4][4]int64{
+		{{1, 0, 0, 0}, {0, 0, 1, 0}, {0, 1, 0, 0}, {0, 0, 0, 1}},    // keep q, swap r and s
+		{{0, 0, 1, 0}, {0, 1, 0, 0}, {1, 0, 0, 0}, {0, 0, 0, 1}},    // keep r, swap q and s
+		{{0, 1, 0, 0}, {1, 0, 0, 0}, {0, 0, 1, 0}, {0, 0, 0, 1}},    // keep s, swap q and r
+		{{0, -1, 0, 0}, {-1, 0, 0, 0}, {0, 0, -1, 0}, {0, 0, 0, 1}}, // fix the s=0 line
+		{{-1, 0, 0, 0}, {0, 0, -1, 0}, {0, -1, 0, 0}, {0, 0, 0, 1}}, // fix the q=0 line
+		{{0, 0, -1, 0}, {0, -1, 0, 0}, {-1, 0, 0, 0}, {0, 0, 0, 1}}, // fix the r=0 line
+	}
 )
 
 func MatrixMultiply(x, y [4][4]int64) [4][4]int64 {
@@ -40,6 +48,13 @@ func RotateMatrix(direction int) [4][4]int64 {
 	return RotationMatrixes[d]
 }
 
+func ReflectMatrix(axis int) [4][4]int64 {
+	if axis < 0 || axis >= len(ReflectionMatrixes) {
+		panic("unknown axis")
+	}
+	return ReflectionMatrixes[axis]
+}
+
 // [[1,0,0,tr]
 // [0,1,0,tq]
 // [0,0,1,0] // this is for s, which is a computed field. ignored.
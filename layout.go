@@ -0,0 +1,144 @@
+package hex
+
+import (
+	"fmt"
+	"math"
+)
+
+// Orientation is the way hexes are rotated when drawn.
+type Orientation byte
+
+const (
+	// PointyTop hexes have a corner pointing up. Direction 0 points along +X.
+	PointyTop Orientation = iota
+	// FlatTop hexes have an edge on top. Direction 0 points 30 degrees
+	// from +X toward +Y.
+	FlatTop
+)
+
+func (o Orientation) String() string {
+	switch o {
+	case PointyTop:
+		return "pointy"
+	case FlatTop:
+		return "flat"
+	default:
+		return "?"
+	}
+}
+
+// orientationMatrix holds the forward (f) and backward (b)
+// matrices that convert between hex and pixel space.
+//
+// startAngle is the angle of corner 0, in multiples of 60 degrees.
+type orientationMatrix struct {
+	f0, f1, f2, f3 float64
+	b0, b1, b2, b3 float64
+	startAngle     float64
+}
+
+var orientations = [2]orientationMatrix{
+	// PointyTop
+	{
+		f0: math.Sqrt(3.0), f1: math.Sqrt(3.0) / 2.0, f2: 0.0, f3: 3.0 / 2.0,
+		b0: math.Sqrt(3.0) / 3.0, b1: -1.0 / 3.0, b2: 0.0, b3: 2.0 / 3.0,
+		startAngle: -0.5,
+	},
+	// FlatTop
+	{
+		f0: 3.0 / 2.0, f1: 0.0, f2: math.Sqrt(3.0) / 2.0, f3: math.Sqrt(3.0),
+		b0: 2.0 / 3.0, b1: 0.0, b2: -1.0 / 3.0, b3: math.Sqrt(3.0) / 3.0,
+		startAngle: 0.0,
+	},
+}
+
+func (o Orientation) matrix() orientationMatrix {
+	if o != PointyTop && o != FlatTop {
+		panic("unknown orientation")
+	}
+	return orientations[o]
+}
+
+// Point is a position in Cartesian (pixel) space.
+type Point struct {
+	X float64
+	Y float64
+}
+
+// String converts the point to a string.
+func (p Point) String() string {
+	return fmt.Sprintf("(%.3f, %.3f)", p.X, p.Y)
+}
+
+// Layout converts between hex coordinates and Cartesian space.
+//
+// Corners are numbered so that corner i is shared by h,
+// h.Neighbor(i), and h.Neighbor(i+1).
+type Layout struct {
+	// Orientation determines whether hexes are pointy-top or flat-top.
+	Orientation Orientation
+	// Size is the distance from the center of a hex to a corner along each axis.
+	// Use different X and Y values to squash hexes.
+	Size Point
+	// Origin is the Cartesian position of the center of the origin hex.
+	Origin Point
+}
+
+// DefaultLayout returns a pointy-top layout with unit-sized hexes
+// centered on 0,0.
+//
+// This is the layout used by HexFractional.ToCartesian.
+func DefaultLayout() Layout {
+	return Layout{
+		Orientation: PointyTop,
+		Size:        Point{X: 1, Y: 1},
+		Origin:      Point{X: 0, Y: 0},
+	}
+}
+
+// HexToPixel returns the Cartesian position of h.
+func (l Layout) HexToPixel(h HexFractional) Point {
+	m := l.Orientation.matrix()
+	return Point{
+		X: (m.f0*h.Q+m.f1*h.R)*l.Size.X + l.Origin.X,
+		Y: (m.f2*h.Q+m.f3*h.R)*l.Size.Y + l.Origin.Y,
+	}
+}
+
+// PixelToHex returns the fractional hex at the Cartesian position p.
+// Use HexFractional.ToHex to find the hex that contains p.
+func (l Layout) PixelToHex(p Point) HexFractional {
+	m := l.Orientation.matrix()
+	x := (p.X - l.Origin.X) / l.Size.X
+	y := (p.Y - l.Origin.Y) / l.Size.Y
+	return HexFractional{
+		Q: m.b0*x + m.b1*y,
+		R: m.b2*x + m.b3*y,
+	}
+}
+
+// cornerOffset returns the offset from a hex center to corner i.
+func (l Layout) cornerOffset(corner int) Point {
+	m := l.Orientation.matrix()
+	angle := 2.0 * math.Pi * (m.startAngle - float64(BoundFacing(corner))) / 6.0
+	return Point{
+		X: l.Size.X * math.Cos(angle),
+		Y: l.Size.Y * math.Sin(angle),
+	}
+}
+
+// HexCorners returns the Cartesian positions of the six corners of h.
+//
+// Corner i is shared by h, h.Neighbor(i), and h.Neighbor(i+1).
+func (l Layout) HexCorners(h Hex) [6]Point {
+	center := l.HexToPixel(h.ToHexFractional())
+	corners := [6]Point{}
+	for i := 0; i < 6; i++ {
+		offset := l.cornerOffset(i)
+		corners[i] = Point{
+			X: center.X + offset.X,
+			Y: center.Y + offset.Y,
+		}
+	}
+	return corners
+}
@@ -0,0 +1,109 @@
+package hex
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testLayouts = []Layout{
+	DefaultLayout(),
+	{Orientation: FlatTop, Size: Point{X: 1, Y: 1}},
+	{Orientation: PointyTop, Size: Point{X: 3, Y: 2}, Origin: Point{X: -10, Y: 4.5}},
+	{Orientation: FlatTop, Size: Point{X: 0.5, Y: 7}, Origin: Point{X: 100, Y: -3}},
+}
+
+func pointsClose(a, b Point) bool {
+	return math.Abs(a.X-b.X) < 1e-9 && math.Abs(a.Y-b.Y) < 1e-9
+}
+
+func TestDefaultLayoutMatchesCartesian(t *testing.T) {
+	l := DefaultLayout()
+	for _, h := range HexArea(Origin(), 10) {
+		hf := h.ToHexFractional().Multiply(0.7)
+		x, y := hf.ToCartesian()
+		p := l.HexToPixel(hf)
+		assert.True(t, pointsClose(Point{X: x, Y: y}, p), "expected (%v, %v), got %s", x, y, p)
+		assert.True(t, hf.AlmostEquals(l.PixelToHex(p)))
+	}
+}
+
+func TestLayoutRoundTrip(t *testing.T) {
+	for _, l := range testLayouts {
+		t.Run(fmt.Sprintf("%s-%s", l.Orientation, l.Size), func(t *testing.T) {
+			for _, h := range HexArea(Origin(), 10) {
+				hf := h.ToHexFractional()
+				require.True(t, hf.AlmostEquals(l.PixelToHex(l.HexToPixel(hf))))
+				require.Equal(t, h, l.PixelToHex(l.HexToPixel(hf)).ToHex())
+			}
+		})
+	}
+}
+
+func TestLayoutCorners(t *testing.T) {
+	for _, l := range testLayouts {
+		t.Run(fmt.Sprintf("%s-%s", l.Orientation, l.Size), func(t *testing.T) {
+			h := Hex{Q: 2, R: -3}
+			corners := l.HexCorners(h)
+			for i := 0; i < 6; i++ {
+				// corner i is the center of mass of the three hexes that share it.
+				expected := l.HexToPixel(Center(h, h.Neighbor(i), h.Neighbor(i+1)))
+				assert.True(t, pointsClose(expected, corners[i]), "corner %d: expected %s, got %s", i, expected, corners[i])
+			}
+		})
+	}
+}
+
+func TestFlatLayoutDirections(t *testing.T) {
+	l := Layout{Orientation: FlatTop, Size: Point{X: 1, Y: 1}}
+	for i := 0; i < 6; i++ {
+		p := l.HexToPixel(Direction(i).ToHexFractional())
+		angle := math.Atan2(p.Y, p.X)
+		expected := math.Pi/6.0 - float64(i)*math.Pi/3.0
+		assert.InDelta(t, 0.0, math.Remainder(angle-expected, 2*math.Pi), 1e-9, "direction %d", i)
+		assert.InDelta(t, math.Sqrt(3), math.Hypot(p.X, p.Y), 1e-9, "direction %d", i)
+	}
+}
+
+func TestCornerPoints(t *testing.T) {
+	for _, h := range HexArea(Origin(), 3) {
+		h = h.Add(Hex{Q: 4, R: -7})
+		cx, cy := h.ToHexFractional().ToCartesian()
+		corners := h.CornerPoints()
+		layoutCorners := DefaultLayout().HexCorners(h)
+		for i, c := range corners {
+			assert.InDelta(t, 1.0, math.Hypot(c.X-cx, c.Y-cy), 1e-9, "corner %d of %s", i, h)
+			assert.True(t, pointsClose(c, layoutCorners[i]), "corner %d of %s", i, h)
+			assert.True(t, h.Corner(i).AlmostEquals(Center(h, h.Neighbor(i), h.Neighbor(i+1))))
+		}
+		// corner 0 is up and to the right on a y-down screen.
+		assert.Greater(t, corners[0].X, cx)
+		assert.Less(t, corners[0].Y, cy)
+	}
+}
+
+func TestEdgeSegments(t *testing.T) {
+	for _, l := range testLayouts {
+		t.Run(fmt.Sprintf("%s-%s", l.Orientation, l.Size), func(t *testing.T) {
+			h := Hex{Q: -1, R: 5}
+			edges := l.HexEdges(h)
+			for i, e := range edges {
+				// neighbors share the edge, but wind the other way.
+				shared := l.HexEdges(h.Neighbor(i))[BoundFacing(i+3)]
+				assert.True(t, pointsClose(e.A, shared.B), "edge %d", i)
+				assert.True(t, pointsClose(e.B, shared.A), "edge %d", i)
+
+				// the midpoint of the edge is halfway to the neighbor.
+				mid := l.HexToPixel(LerpHexFractional(h.ToHexFractional(), h.Neighbor(i).ToHexFractional(), 0.5))
+				assert.True(t, pointsClose(mid, Point{X: (e.A.X + e.B.X) / 2, Y: (e.A.Y + e.B.Y) / 2}), "edge %d", i)
+			}
+		})
+	}
+	layoutEdges := DefaultLayout().HexEdges(Origin())
+	for i, e := range Origin().EdgeSegments() {
+		assert.True(t, pointsClose(e.A, layoutEdges[i].A) && pointsClose(e.B, layoutEdges[i].B), "edge %d", i)
+	}
+}
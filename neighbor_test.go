@@ -0,0 +1,104 @@
+package hex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func neighborTestHexes() []Hex {
+	hexes := Spiral(Origin(), 4)
+	return append(hexes, Hex{Q: 1000, R: -77}, Hex{Q: -123456, R: 654321})
+}
+
+func TestNeighborsCount(t *testing.T) {
+	for _, h := range neighborTestHexes() {
+		neighbors := h.Neighbors()
+		require.Len(t, neighbors, 6, "neighbors of %s", h)
+
+		arr := h.NeighborArray()
+		require.Equal(t, arr[:], neighbors)
+
+		unique := make(map[Hex]bool)
+		for i, n := range neighbors {
+			require.Equal(t, h.Neighbor(i), n, "direction %d of %s", i, h)
+			require.Equal(t, Direction(i), n.Subtract(h), "direction %d of %s", i, h)
+			unique[n] = true
+		}
+		require.Len(t, unique, 6, "neighbors of %s", h)
+		require.NotContains(t, unique, h)
+	}
+}
+
+func TestNeighborsAreDistanceOne(t *testing.T) {
+	for _, h := range neighborTestHexes() {
+		neighbors := h.Neighbors()
+		for _, n := range neighbors {
+			require.EqualValues(t, 1, h.DistanceTo(n))
+			require.EqualValues(t, 1, n.DistanceTo(h))
+		}
+
+		// every hex at distance one is a neighbor.
+		for q := h.Q - 2; q <= h.Q+2; q++ {
+			for r := h.R - 2; r <= h.R+2; r++ {
+				x := Hex{Q: q, R: r}
+				if h.DistanceTo(x) == 1 {
+					require.Contains(t, neighbors, x, "%s next to %s", x, h)
+				} else {
+					require.NotContains(t, neighbors, x, "%s next to %s", x, h)
+				}
+			}
+		}
+	}
+}
+
+func TestNeighborsAreReciprocal(t *testing.T) {
+	for _, h := range neighborTestHexes() {
+		for i := 0; i < 6; i++ {
+			n := h.Neighbor(i)
+			require.Equal(t, h, n.Neighbor(i+3), "direction %d of %s", i, h)
+			require.Contains(t, n.Neighbors(), h)
+			require.Equal(t, Origin(), Direction(i).Add(Direction(i+3)))
+		}
+	}
+}
+
+func TestNeighborDirectionsWrap(t *testing.T) {
+	h := Hex{Q: 3, R: -8}
+	for i := -12; i <= 12; i++ {
+		require.Equal(t, h.Neighbor(BoundFacing(i)), h.Neighbor(i), "direction %d", i)
+	}
+	assert.Equal(t, h.Neighbor(5), h.Neighbor(-1))
+	assert.Equal(t, h.Neighbor(0), h.Neighbor(6))
+}
+
+func TestNeighborsRotationInvariant(t *testing.T) {
+	pivot := Hex{Q: -2, R: 5}
+	for _, h := range neighborTestHexes() {
+		for d := 0; d < 6; d++ {
+			rotated := h.Rotate(pivot, d)
+			rotatedNeighbors := rotated.Neighbors()
+			for i, n := range h.Neighbors() {
+				// rotating a neighbor gives the neighbor of the rotated hex,
+				// in the rotated direction.
+				require.Equal(t, rotatedNeighbors[BoundFacing(i+d)], n.Rotate(pivot, d), "direction %d of %s rotated by %d", i, h, d)
+			}
+			// rotating about a hex keeps its neighbors as neighbors.
+			for i := 0; i < 6; i++ {
+				require.Equal(t, h.Neighbor(i+d), h.Neighbor(i).Rotate(h, d))
+			}
+		}
+	}
+}
+
+func TestNeighborsReflectionInvariant(t *testing.T) {
+	for _, h := range neighborTestHexes() {
+		for _, axis := range []Axis{AxisQ, AxisR, AxisS, AxisQR, AxisRS, AxisSQ} {
+			reflected := h.Reflect(axis).Neighbors()
+			for _, n := range h.Neighbors() {
+				require.Contains(t, reflected, n.Reflect(axis))
+			}
+		}
+	}
+}
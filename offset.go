@@ -0,0 +1,107 @@
+package hex
+
+import "fmt"
+
+// OffsetParity selects which rows or columns of an offset grid are shoved over.
+type OffsetParity byte
+
+const (
+	// OddR shoves odd rows right by half a hex. Use with pointy-top hexes.
+	OddR OffsetParity = iota
+	// EvenR shoves even rows right by half a hex. Use with pointy-top hexes.
+	EvenR
+	// OddQ shoves odd columns down by half a hex. Use with flat-top hexes.
+	OddQ
+	// EvenQ shoves even columns down by half a hex. Use with flat-top hexes.
+	EvenQ
+)
+
+func (p OffsetParity) String() string {
+	switch p {
+	case OddR:
+		return "odd-r"
+	case EvenR:
+		return "even-r"
+	case OddQ:
+		return "odd-q"
+	case EvenQ:
+		return "even-q"
+	default:
+		return "?"
+	}
+}
+
+// OffsetCoord is a coordinate in a row/column offset grid.
+//
+// An OffsetCoord only has meaning alongside the OffsetParity
+// it was created with.
+type OffsetCoord struct {
+	Col int64
+	Row int64
+}
+
+// parityBit returns 1 if k is odd, 0 otherwise.
+// This is correct for negative numbers too.
+func parityBit(k int64) int64 {
+	return k & 1
+}
+
+// ToOffset converts the hex into an offset coordinate with the given parity.
+func (h Hex) ToOffset(parity OffsetParity) OffsetCoord {
+	switch parity {
+	case OddR:
+		return OffsetCoord{
+			Col: h.Q + (h.R-parityBit(h.R))/2,
+			Row: h.R,
+		}
+	case EvenR:
+		return OffsetCoord{
+			Col: h.Q + (h.R+parityBit(h.R))/2,
+			Row: h.R,
+		}
+	case OddQ:
+		return OffsetCoord{
+			Col: h.Q,
+			Row: h.R + (h.Q-parityBit(h.Q))/2,
+		}
+	case EvenQ:
+		return OffsetCoord{
+			Col: h.Q,
+			Row: h.R + (h.Q+parityBit(h.Q))/2,
+		}
+	}
+	panic("unknown offset parity")
+}
+
+// ToHex converts the offset coordinate back into a hex.
+// parity must be the same one used to create the offset coordinate.
+func (o OffsetCoord) ToHex(parity OffsetParity) Hex {
+	switch parity {
+	case OddR:
+		return Hex{
+			Q: o.Col - (o.Row-parityBit(o.Row))/2,
+			R: o.Row,
+		}
+	case EvenR:
+		return Hex{
+			Q: o.Col - (o.Row+parityBit(o.Row))/2,
+			R: o.Row,
+		}
+	case OddQ:
+		return Hex{
+			Q: o.Col,
+			R: o.Row - (o.Col-parityBit(o.Col))/2,
+		}
+	case EvenQ:
+		return Hex{
+			Q: o.Col,
+			R: o.Row - (o.Col+parityBit(o.Col))/2,
+		}
+	}
+	panic("unknown offset parity")
+}
+
+// String converts the offset coordinate to a string.
+func (o OffsetCoord) String() string {
+	return fmt.Sprintf("[%v, %v]", o.Col, o.Row)
+}
@@ -0,0 +1,52 @@
+package hex
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var allParities = []OffsetParity{OddR, EvenR, OddQ, EvenQ}
+
+func TestOffsetRoundTrip(t *testing.T) {
+	testHexes := HexArea(Origin(), 10)
+	for _, parity := range allParities {
+		t.Run(parity.String(), func(t *testing.T) {
+			seen := make(map[OffsetCoord]Hex)
+			for _, h := range testHexes {
+				h = h.Add(Hex{Q: -3, R: 7})
+				o := h.ToOffset(parity)
+				if prev, ok := seen[o]; ok {
+					require.FailNow(t, "collision", "%s and %s both map to %s", prev, h, o)
+				}
+				seen[o] = h
+				require.Equal(t, h, o.ToHex(parity), "offset %s", o)
+			}
+		})
+	}
+}
+
+func TestOffsetKnownValues(t *testing.T) {
+	tests := []struct {
+		parity OffsetParity
+		h      Hex
+		o      OffsetCoord
+	}{
+		{OddR, Hex{Q: 0, R: 0}, OffsetCoord{Col: 0, Row: 0}},
+		{OddR, Hex{Q: 0, R: 1}, OffsetCoord{Col: 0, Row: 1}},
+		{OddR, Hex{Q: 0, R: -1}, OffsetCoord{Col: -1, Row: -1}},
+		{OddR, Hex{Q: -1, R: 2}, OffsetCoord{Col: 0, Row: 2}},
+		{EvenR, Hex{Q: 0, R: 1}, OffsetCoord{Col: 1, Row: 1}},
+		{EvenR, Hex{Q: 0, R: -1}, OffsetCoord{Col: 0, Row: -1}},
+		{OddQ, Hex{Q: 1, R: 0}, OffsetCoord{Col: 1, Row: 0}},
+		{OddQ, Hex{Q: -1, R: 0}, OffsetCoord{Col: -1, Row: -1}},
+		{EvenQ, Hex{Q: 1, R: 0}, OffsetCoord{Col: 1, Row: 1}},
+		{EvenQ, Hex{Q: -1, R: 0}, OffsetCoord{Col: -1, Row: 0}},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.o, test.h.ToOffset(test.parity), fmt.Sprintf("%s %s", test.parity, test.h))
+		assert.Equal(t, test.h, test.o.ToHex(test.parity), fmt.Sprintf("%s %s", test.parity, test.o))
+	}
+}
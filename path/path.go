@@ -1,7 +1,6 @@
 package path
 
 import (
-	"container/heap"
 	"sync"
 
 	"github.com/erinpentecost/hex"
@@ -15,44 +14,84 @@ type aStarInfo struct {
 	cost int
 }
 
-// unwind walks a field map backwards into a path
-func unwind(field map[hex.Hex]aStarInfo, origin hex.Hex, destination hex.Hex) []hex.Hex {
-	path := make([]hex.Hex, 0)
-	// Unwind to get path
+// wind walks a field map backwards from destination to origin,
+// appending each hex to path.
+func wind(path []hex.Hex, field map[hex.Hex]aStarInfo, origin hex.Hex, destination hex.Hex) []hex.Hex {
 	cur := destination
-	curExtras := field[cur]
-
-	// Begin unwind
 	for {
-		path = append([]hex.Hex{cur}, path...)
+		path = append(path, cur)
 
 		if cur == origin {
 			return path
 		}
 
-		cur = curExtras.parent
-		curExtras = field[cur]
+		cur = field[cur].parent
 	}
 }
 
-// wind walks a field map forwards into a path
-func wind(field map[hex.Hex]aStarInfo, origin hex.Hex, destination hex.Hex) []hex.Hex {
-	path := make([]hex.Hex, 0)
-	// Unwind to get path
-	cur := destination
-	curExtras := field[cur]
+// unwind walks a field map backwards into a path
+// that starts at origin.
+func unwind(path []hex.Hex, field map[hex.Hex]aStarInfo, origin hex.Hex, destination hex.Hex) []hex.Hex {
+	start := len(path)
+	path = wind(path, field, origin, destination)
+	for i, j := start, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
 
-	// Begin unwind
-	for {
-		path = append(path, cur)
+// stepper enumerates the moves that can be made from a hex.
+//
+// Steps 0 through 5 are moves to neighbors.
+// Steps 6 through 11 are moves to diagonal neighbors, and are
+// only used if the pather is a DiagonalPather.
+//
+// Hexes are wrapped to the pather's topology if it is a TopologyPather.
+type stepper struct {
+	pather   Pather
+	diagonal DiagonalPather
+	topology hex.Topology
+	count    int
+}
 
-		if cur == origin {
-			return path
-		}
+func newStepper(pather Pather) stepper {
+	s := stepper{
+		pather:   pather,
+		topology: hex.Planar{},
+		count:    6,
+	}
+	if d, ok := pather.(DiagonalPather); ok {
+		s.diagonal = d
+		s.count = 12
+	}
+	if t, ok := pather.(TopologyPather); ok {
+		s.topology = t.Topology()
+	}
+	return s
+}
+
+// next returns the hex reached by taking step i from h.
+func (s stepper) next(h hex.Hex, i int) hex.Hex {
+	if i < 6 {
+		return s.topology.Neighbor(h, i)
+	}
+	return s.topology.Canonical(h.DiagonalNeighbor(i - 6))
+}
 
-		cur = curExtras.parent
-		curExtras = field[cur]
+// cost returns the cost of taking step i from h.
+func (s stepper) cost(h hex.Hex, i int) int {
+	if i < 6 {
+		return s.pather.Cost(h, i)
 	}
+	return s.diagonal.DiagonalCost(h, i-6)
+}
+
+// reverse returns the step that undoes step i.
+func (s stepper) reverse(i int) int {
+	if i < 6 {
+		return hex.BoundFacing(i + 3)
+	}
+	return hex.BoundFacing(i-6+3) + 6
 }
 
 // To finds a near-optimal path to the target hex.
@@ -62,6 +101,12 @@ func wind(field map[hex.Hex]aStarInfo, origin hex.Hex, destination hex.Hex) []he
 //
 // If there is no path, this will be empty.
 //
+// If pather is a DiagonalPather, the path may also include
+// steps to diagonal neighbors.
+//
+// If pather is a TopologyPather, from and target are wrapped
+// to the topology, and the path may cross its seams.
+//
 // This is an offline search algorithm; there is no caching.
 func To(from hex.Hex, target hex.Hex, pather Pather) (path []hex.Hex) {
 
@@ -77,6 +122,10 @@ func To(from hex.Hex, target hex.Hex, pather Pather) (path []hex.Hex) {
 	// Init output variables
 	path = make([]hex.Hex, 0)
 
+	steps := newStepper(pather)
+	from = steps.topology.Canonical(from)
+	target = steps.topology.Canonical(target)
+
 	// Base case.
 	if from == target {
 		path = append(path, from)
@@ -99,21 +148,20 @@ func To(from hex.Hex, target hex.Hex, pather Pather) (path []hex.Hex) {
 
 	targetMux := sync.Mutex{}
 	go func() {
-		targetPQ := &priorityQueue{&pqItem{
+		targetPQ := &priorityQueue{pqItem{
 			Value:    target,
 			Priority: 0,
-			Index:    0,
 		}}
-		heap.Init(targetPQ)
 
 		// Cycle through all the neigbors starting at `target`
 		for targetPQ.Len() > 0 {
-			targetFrontier := (*(heap.Pop(targetPQ).(*pqItem))).Value
+			targetFrontier := targetPQ.pop().Value
 
 			// Look at all neighbors
-			for i, next := range targetFrontier.Neighbors() {
+			for i := 0; i < steps.count; i++ {
+				next := steps.next(targetFrontier, i)
 				// edgeCost is reversed here
-				edgeCost := pather.Cost(next, hex.BoundFacing(i+3))
+				edgeCost := steps.cost(next, steps.reverse(i))
 				// Negative costs are a special case
 				if edgeCost < 0 {
 					continue
@@ -139,7 +187,7 @@ func To(from hex.Hex, target hex.Hex, pather Pather) (path []hex.Hex) {
 					if stop {
 						return
 					}
-					heap.Push(targetPQ, &pqItem{
+					targetPQ.push(pqItem{
 						Value: next,
 						// estimatedCost is reversed here
 						Priority: newCost + pather.EstimatedCost(from, next),
@@ -152,25 +200,22 @@ func To(from hex.Hex, target hex.Hex, pather Pather) (path []hex.Hex) {
 		// no solution if we get to here
 	}()
 
-	fromPQ := &priorityQueue{&pqItem{
+	fromPQ := &priorityQueue{pqItem{
 		Value:    from,
 		Priority: 0,
-		Index:    0,
 	}}
-	heap.Init(fromPQ)
 
 	// Cycle through all the neigbors starting at `from`
 	for fromPQ.Len() > 0 {
-		fromFrontier := (*(heap.Pop(fromPQ).(*pqItem))).Value
+		fromFrontier := fromPQ.pop().Value
 
 		// Quit if the fromFrontier hit a visited node in the targetPaths.
 		targetMux.Lock()
 		if _, ok := targetPaths[fromFrontier]; ok {
-			firstSection := unwind(fromPaths, from, fromFrontier)
-			secondSection := wind(targetPaths, target, fromFrontier)
-
 			// join em!
-			path = append(firstSection, secondSection[1:]...)
+			// fromFrontier is in both sections, so drop it from the second.
+			path = unwind(path, fromPaths, from, fromFrontier)
+			path = wind(path[:len(path)-1], targetPaths, target, fromFrontier)
 
 			// let the other A* know to quit
 			targetPaths = nil
@@ -182,8 +227,9 @@ func To(from hex.Hex, target hex.Hex, pather Pather) (path []hex.Hex) {
 		targetMux.Unlock()
 
 		// Look at all neighbors
-		for i, next := range fromFrontier.Neighbors() {
-			edgeCost := pather.Cost(fromFrontier, i)
+		for i := 0; i < steps.count; i++ {
+			next := steps.next(fromFrontier, i)
+			edgeCost := steps.cost(fromFrontier, i)
 			// Negative costs are a special case
 			if edgeCost < 0 {
 				continue
@@ -198,7 +244,7 @@ func To(from hex.Hex, target hex.Hex, pather Pather) (path []hex.Hex) {
 					parent: fromFrontier,
 					cost:   newCost,
 				}
-				heap.Push(fromPQ, &pqItem{
+				fromPQ.push(pqItem{
 					Value:    next,
 					Priority: newCost + pather.EstimatedCost(next, target),
 				})
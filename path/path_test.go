@@ -43,8 +43,20 @@ func (p patherImp) EstimatedCost(a, b hex.Hex) int {
 	return 0
 }
 
-func ring(center hex.Hex, radius int64) *area.Area {
-	return area.BigHex(center, radius).Subtract(area.BigHex(center, radius-1)).Build()
+type diagonalPatherImp struct {
+	patherImp
+}
+
+func (p diagonalPatherImp) DiagonalCost(a hex.Hex, direction int) int {
+	v, ok := p.cost[a.DiagonalNeighbor(direction)]
+	if ok {
+		return v
+	}
+	return 1
+}
+
+func (p diagonalPatherImp) EstimatedCost(a, b hex.Hex) int {
+	return int(a.DiagonalDistanceTo(b))
 }
 
 func concentricMaze(maxSize int64) *area.Area {
@@ -53,7 +65,7 @@ func concentricMaze(maxSize int64) *area.Area {
 	for i := int64(2); i < maxSize; i = i + 2 {
 		opening := i
 		cur := int64(0)
-		for _, h := range ring(hex.Origin(), i).Slice() {
+		for _, h := range hex.Ring(hex.Origin(), i) {
 			cur++
 			if opening != cur {
 				c = c.Union(area.NewArea(h))
@@ -101,7 +113,7 @@ func pathCheck(t *testing.T, target hex.Hex, pather path.Pather) {
 
 func TestDirectPaths(t *testing.T) {
 	for i := int64(1); i < 11; i = i + 2 {
-		for _, h := range ring(hex.Origin(), i).Slice() {
+		for _, h := range hex.Ring(hex.Origin(), i) {
 			t.Run(fmt.Sprintf("to-%s", h.String()), func(t *testing.T) {
 				pathCheck(t, h, newPatherImp(area.NewArea()))
 			})
@@ -111,7 +123,7 @@ func TestDirectPaths(t *testing.T) {
 
 func TestIndirectPaths(t *testing.T) {
 	for i := int64(1); i < 11; i = i + 2 {
-		for _, h := range ring(hex.Origin(), i).Slice() {
+		for _, h := range hex.Ring(hex.Origin(), i) {
 			t.Run(fmt.Sprintf("to-%s", h.String()), func(t *testing.T) {
 				pathCheck(t, h, newPatherImp(concentricMaze(h.Length()+4)))
 			})
@@ -119,21 +131,133 @@ func TestIndirectPaths(t *testing.T) {
 	}
 }
 
+func TestDiagonalPaths(t *testing.T) {
+	pather := diagonalPatherImp{newPatherImp(area.NewArea())}
+	for _, h := range hex.Spiral(hex.Origin(), 8) {
+		found := path.To(hex.Origin(), h, pather)
+		require.NotEmpty(t, found)
+		assert.Equal(t, hex.Origin(), found[0])
+		assert.Equal(t, h, found[len(found)-1])
+		// the search is near-optimal, so allow some slack.
+		assert.GreaterOrEqual(t, int64(len(found)), hex.Origin().DiagonalDistanceTo(h)+1, "path to %s is too short", h)
+		assert.LessOrEqual(t, int64(len(found)), hex.Origin().DistanceTo(h)+1, "path to %s doesn't use diagonals", h)
+		for i := 1; i < len(found); i++ {
+			require.EqualValues(t, 1, found[i-1].DiagonalDistanceTo(found[i]), "path to %s is not contiguous", h)
+		}
+	}
+}
+
+func TestDiagonalPathAroundWall(t *testing.T) {
+	// a solid wall that diagonal steps can't cut through
+	// without landing on it.
+	walls := area.Line(hex.Hex{Q: 2, R: -6}, hex.Hex{Q: 2, R: 6})
+	pather := diagonalPatherImp{newPatherImp(walls)}
+	target := hex.Hex{Q: 5, R: 0}
+	found := path.To(hex.Origin(), target, pather)
+	require.NotEmpty(t, found)
+	for _, h := range found {
+		require.False(t, walls.ContainsHexes(h), "path goes through wall at %s", h)
+	}
+	assert.Equal(t, target, found[len(found)-1])
+}
+
 func TestNoPath(t *testing.T) {
 	t.Parallel()
 
-	pather := newPatherImp(ring(hex.Origin(), 5))
+	pather := newPatherImp(area.Ring(hex.Origin(), 5))
 
 	foundPath := path.To(hex.Origin(), hex.Hex{Q: 100, R: 100}, pather)
 	require.Empty(t, foundPath)
 }
 
 func BenchmarkDirectPath(b *testing.B) {
+	b.ReportAllocs()
 	var foundPath []hex.Hex
 	target := hex.Hex{Q: 10, R: 10}
 	pather := newPatherImp(area.NewArea())
 	for i := 0; i < b.N; i++ {
 		foundPath = path.To(hex.Origin(), target, pather)
 	}
-	assert.Equal(b, hex.Origin().DistanceTo(target)+1, len(foundPath))
+	assert.EqualValues(b, hex.Origin().DistanceTo(target)+1, len(foundPath))
+}
+
+func BenchmarkMazePath(b *testing.B) {
+	b.ReportAllocs()
+	var foundPath []hex.Hex
+	target := hex.Hex{Q: 9, R: -2}
+	pather := newPatherImp(concentricMaze(13))
+	for i := 0; i < b.N; i++ {
+		foundPath = path.To(hex.Origin(), target, pather)
+	}
+	assert.NotEmpty(b, foundPath)
+}
+
+type topologyPatherImp struct {
+	patherImp
+	topology hex.Topology
+}
+
+func (p topologyPatherImp) Cost(a hex.Hex, direction int) int {
+	v, ok := p.cost[p.topology.Neighbor(a, direction)]
+	if ok {
+		return v
+	}
+	return 1
+}
+
+func (p topologyPatherImp) EstimatedCost(a, b hex.Hex) int {
+	return int(p.topology.DistanceTo(a, b))
+}
+
+func (p topologyPatherImp) Topology() hex.Topology {
+	return p.topology
+}
+
+func TestPathAcrossSeam(t *testing.T) {
+	cylinder := hex.Cylinder{Width: 20}
+	pather := topologyPatherImp{
+		patherImp: newPatherImp(area.NewArea()),
+		topology:  cylinder,
+	}
+
+	from := hex.Hex{Q: 1, R: 0}
+	target := hex.Hex{Q: 18, R: 0}
+	p := path.To(from, target, pather)
+	require.NotEmpty(t, p)
+	assert.Equal(t, from, p[0])
+	assert.Equal(t, target, p[len(p)-1])
+	// going west across the seam is shorter.
+	assert.Len(t, p, 4)
+	for i := 1; i < len(p); i++ {
+		assert.EqualValues(t, 1, cylinder.DistanceTo(p[i-1], p[i]))
+		assert.Equal(t, p[i], cylinder.Canonical(p[i]))
+	}
+
+	// non-canonical endpoints are wrapped.
+	p = path.To(hex.Hex{Q: 21, R: 0}, hex.Hex{Q: -2, R: 0}, pather)
+	assert.Equal(t, from, p[0])
+	assert.Equal(t, target, p[len(p)-1])
+}
+
+func TestPathOnTorus(t *testing.T) {
+	torus := hex.HexTorus{Radius: 6}
+	// a wall across the middle of the world can be walked around
+	// by going off the edge.
+	wall := area.Line(hex.Hex{Q: -6, R: 3}, hex.Hex{Q: 6, R: -3})
+	pather := topologyPatherImp{
+		patherImp: newPatherImp(wall),
+		topology:  torus,
+	}
+
+	from := hex.Hex{Q: 0, R: -2}
+	target := hex.Hex{Q: 0, R: 2}
+	p := path.To(from, target, pather)
+	require.NotEmpty(t, p)
+	assert.Equal(t, from, p[0])
+	assert.Equal(t, target, p[len(p)-1])
+	for i := 1; i < len(p); i++ {
+		assert.EqualValues(t, 1, torus.DistanceTo(p[i-1], p[i]))
+		assert.LessOrEqual(t, p[i].Length(), int64(6))
+		assert.False(t, wall.ContainsHexes(p[i]))
+	}
 }
@@ -14,3 +14,32 @@ type Pather interface {
 	// Negative costs are treated as impassable.
 	EstimatedCost(a, b hex.Hex) int
 }
+
+// DiagonalPather is a Pather that can also step to diagonal neighbors.
+//
+// When the Pather passed to To is also a DiagonalPather, each hex's six
+// diagonal neighbors are searched along with its six neighbors.
+// hex.Hex.DiagonalDistanceTo is the matching heuristic for EstimatedCost
+// when every step costs the same.
+type DiagonalPather interface {
+	Pather
+
+	// DiagonalCost indicates the move cost between a hex and one
+	// of its diagonal neighbors. Higher values are less desirable.
+	// Negative costs are treated as impassable.
+	DiagonalCost(a hex.Hex, direction int) int
+}
+
+// TopologyPather is a Pather for a world that wraps around.
+//
+// When the Pather passed to To is also a TopologyPather, every hex
+// in the path is canonical for the topology, and paths may cross
+// the seams of the world. Consecutive hexes in such a path are
+// neighbors in the topology, but not necessarily on the plane.
+// EstimatedCost should use the topology's DistanceTo.
+type TopologyPather interface {
+	Pather
+
+	// Topology returns the shape of the world.
+	Topology() hex.Topology
+}
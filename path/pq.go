@@ -5,35 +5,64 @@ import "github.com/erinpentecost/hex"
 type pqItem struct {
 	Value    hex.Hex
 	Priority int
-	Index    int
 }
 
-type priorityQueue []*pqItem
+// priorityQueue is a binary min-heap of pqItems.
+//
+// This works like container/heap, but stores items by value
+// so pushing doesn't allocate.
+type priorityQueue []pqItem
 
 func (pq priorityQueue) Len() int { return len(pq) }
 
-func (pq priorityQueue) Less(i, j int) bool {
+func (pq priorityQueue) less(i, j int) bool {
 	return pq[i].Priority < pq[j].Priority
 }
 
-func (pq priorityQueue) Swap(i, j int) {
+func (pq priorityQueue) swap(i, j int) {
 	pq[i], pq[j] = pq[j], pq[i]
-	pq[i].Index = i
-	pq[j].Index = j
 }
 
-func (pq *priorityQueue) Push(x interface{}) {
-	n := len(*pq)
-	item := x.(*pqItem)
-	item.Index = n
+func (pq *priorityQueue) push(item pqItem) {
 	*pq = append(*pq, item)
+	pq.up(len(*pq) - 1)
 }
 
-func (pq *priorityQueue) Pop() interface{} {
+func (pq *priorityQueue) pop() pqItem {
 	old := *pq
-	n := len(old)
-	item := old[n-1]
-	item.Index = -1
-	*pq = old[0 : n-1]
+	n := len(old) - 1
+	old.swap(0, n)
+	old[:n].down(0)
+	item := old[n]
+	*pq = old[:n]
 	return item
 }
+
+func (pq priorityQueue) up(j int) {
+	for {
+		i := (j - 1) / 2 // parent
+		if i == j || !pq.less(j, i) {
+			break
+		}
+		pq.swap(i, j)
+		j = i
+	}
+}
+
+func (pq priorityQueue) down(i int) {
+	n := len(pq)
+	for {
+		j := 2*i + 1
+		if j >= n || j < 0 {
+			break
+		}
+		if r := j + 1; r < n && pq.less(r, j) {
+			j = r
+		}
+		if !pq.less(j, i) {
+			break
+		}
+		pq.swap(i, j)
+		i = j
+	}
+}
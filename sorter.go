@@ -4,8 +4,115 @@ import "sort"
 
 var (
 	_ sort.Interface = (Sort)(nil)
+	_ sort.Interface = (MortonSort)(nil)
+	_ sort.Interface = (HilbertSort)(nil)
 )
 
+// Ordering reports whether hex a sorts before hex b.
+//
+// Every Ordering in this package is a strict total order: for any
+// two different hexes, exactly one sorts before the other.
+type Ordering func(a, b Hex) bool
+
+// Sort sorts the hexes in place.
+func (o Ordering) Sort(hexes []Hex) {
+	sort.Sort(o.Interface(hexes))
+}
+
+// Interface wraps the hexes so they can be passed to sort.Sort.
+func (o Ordering) Interface(hexes []Hex) sort.Interface {
+	return orderedHexes{hexes: hexes, less: o}
+}
+
+// SliceLess returns a less function for the hexes
+// so they can be passed to sort.Slice.
+func (o Ordering) SliceLess(hexes []Hex) func(i, j int) bool {
+	return func(i, j int) bool {
+		return o(hexes[i], hexes[j])
+	}
+}
+
+type orderedHexes struct {
+	hexes []Hex
+	less  Ordering
+}
+
+func (s orderedHexes) Len() int {
+	return len(s.hexes)
+}
+
+func (s orderedHexes) Swap(i int, j int) {
+	s.hexes[i], s.hexes[j] = s.hexes[j], s.hexes[i]
+}
+
+func (s orderedHexes) Less(i int, j int) bool {
+	return s.less(s.hexes[i], s.hexes[j])
+}
+
+// RowMajor orders hexes by R, then by Q.
+func RowMajor(a, b Hex) bool {
+	if a.R != b.R {
+		return a.R < b.R
+	}
+	return a.Q < b.Q
+}
+
+// ColumnMajor orders hexes by Q, then by R.
+func ColumnMajor(a, b Hex) bool {
+	if a.Q != b.Q {
+		return a.Q < b.Q
+	}
+	return a.R < b.R
+}
+
+// DistanceFrom orders hexes by their distance to point.
+// Hexes that are the same distance away are ordered with RowMajor.
+func DistanceFrom(point Hex) Ordering {
+	return func(a, b Hex) bool {
+		da, db := a.DistanceTo(point), b.DistanceTo(point)
+		if da != db {
+			return da < db
+		}
+		return RowMajor(a, b)
+	}
+}
+
+// SpiralFrom orders hexes the same way Spiral does:
+// by their distance to center, then by their position
+// in the Ring around center.
+func SpiralFrom(center Hex) Ordering {
+	return func(a, b Hex) bool {
+		da, db := a.DistanceTo(center), b.DistanceTo(center)
+		if da != db {
+			return da < db
+		}
+		return ringIndex(a.Subtract(center)) < ringIndex(b.Subtract(center))
+	}
+}
+
+// ringIndex returns the position of offset in Ring(Origin(), offset.Length()).
+func ringIndex(offset Hex) int64 {
+	k := offset.Length()
+	q, r, s := offset.Q, offset.R, offset.S()
+	switch {
+	case k == 0:
+		return 0
+	case r == k && q < 0:
+		return q + k
+	case s == -k && q < k:
+		return k + q
+	case q == k && r <= 0 && r > -k:
+		return 2*k - r
+	case r == -k && q > 0:
+		return 3*k + k - q
+	case s == k && q <= 0 && q > -k:
+		return 4*k - q
+	default:
+		return 5*k + r
+	}
+}
+
+// Sort orders hexes with RowMajor.
 type Sort []Hex
 
 func (s Sort) Len() int {
@@ -17,8 +124,39 @@ func (s Sort) Swap(i int, j int) {
 }
 
 func (s Sort) Less(i int, j int) bool {
-	if s[i].Q > s[j].Q {
-		return true
-	}
-	return s[i].R > s[j].R
+	return RowMajor(s[i], s[j])
+}
+
+// MortonSort orders hexes by MortonKey.
+//
+// Use it like sort.Sort(hex.MortonSort(area.Slice())).
+type MortonSort []Hex
+
+func (s MortonSort) Len() int {
+	return len(s)
+}
+
+func (s MortonSort) Swap(i int, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+func (s MortonSort) Less(i int, j int) bool {
+	return s[i].MortonKey() < s[j].MortonKey()
+}
+
+// HilbertSort orders hexes by HilbertKey.
+//
+// Use it like sort.Sort(hex.HilbertSort(area.Slice())).
+type HilbertSort []Hex
+
+func (s HilbertSort) Len() int {
+	return len(s)
+}
+
+func (s HilbertSort) Swap(i int, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+func (s HilbertSort) Less(i int, j int) bool {
+	return s[i].HilbertKey() < s[j].HilbertKey()
 }
@@ -0,0 +1,105 @@
+package hex
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testOrderings() map[string]Ordering {
+	return map[string]Ordering{
+		"row-major":     RowMajor,
+		"column-major":  ColumnMajor,
+		"distance-from": DistanceFrom(Hex{Q: 2, R: -1}),
+		"spiral-from":   SpiralFrom(Hex{Q: -1, R: 3}),
+		"sort": func(a, b Hex) bool {
+			return Sort{a, b}.Less(0, 1)
+		},
+		"morton": func(a, b Hex) bool {
+			return MortonSort{a, b}.Less(0, 1)
+		},
+		"hilbert": func(a, b Hex) bool {
+			return HilbertSort{a, b}.Less(0, 1)
+		},
+	}
+}
+
+func TestOrderingsAreStrictWeak(t *testing.T) {
+	rng := rand.New(rand.NewSource(14))
+	hexes := Spiral(Origin(), 4)
+	for i := 0; i < 40; i++ {
+		hexes = append(hexes, Hex{Q: rng.Int63n(21) - 10, R: rng.Int63n(21) - 10})
+	}
+
+	for name, less := range testOrderings() {
+		t.Run(name, func(t *testing.T) {
+			for _, a := range hexes {
+				// irreflexive
+				require.False(t, less(a, a), "%s < %s", a, a)
+				for _, b := range hexes {
+					// asymmetric, and total: different hexes are never equivalent.
+					if a == b {
+						continue
+					}
+					require.NotEqual(t, less(a, b), less(b, a), "%s and %s", a, b)
+					for _, c := range hexes {
+						// transitive
+						if less(a, b) && less(b, c) {
+							require.True(t, less(a, c), "%s < %s < %s", a, b, c)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestOrderingsSort(t *testing.T) {
+	for name, less := range testOrderings() {
+		t.Run(name, func(t *testing.T) {
+			hexes := Spiral(Hex{Q: 5, R: 5}, 5)
+			rand.New(rand.NewSource(3)).Shuffle(len(hexes), func(i, j int) {
+				hexes[i], hexes[j] = hexes[j], hexes[i]
+			})
+
+			sorted := append([]Hex{}, hexes...)
+			sort.Sort(Ordering(less).Interface(sorted))
+			require.True(t, sort.SliceIsSorted(sorted, Ordering(less).SliceLess(sorted)))
+
+			sliced := append([]Hex{}, hexes...)
+			sort.Slice(sliced, Ordering(less).SliceLess(sliced))
+			assert.Equal(t, sorted, sliced)
+
+			Ordering(less).Sort(hexes)
+			assert.Equal(t, sorted, hexes)
+		})
+	}
+}
+
+func TestSpiralFrom(t *testing.T) {
+	center := Hex{Q: 4, R: -7}
+	expected := Spiral(center, 6)
+
+	hexes := append([]Hex{}, expected...)
+	rand.New(rand.NewSource(6)).Shuffle(len(hexes), func(i, j int) {
+		hexes[i], hexes[j] = hexes[j], hexes[i]
+	})
+	SpiralFrom(center).Sort(hexes)
+	assert.Equal(t, expected, hexes)
+}
+
+func TestRowAndColumnMajor(t *testing.T) {
+	hexes := []Hex{{Q: 1, R: 1}, {Q: 0, R: 1}, {Q: 1, R: 0}, {Q: 0, R: 0}}
+
+	Ordering(RowMajor).Sort(hexes)
+	assert.Equal(t, []Hex{{Q: 0, R: 0}, {Q: 1, R: 0}, {Q: 0, R: 1}, {Q: 1, R: 1}}, hexes)
+
+	Ordering(ColumnMajor).Sort(hexes)
+	assert.Equal(t, []Hex{{Q: 0, R: 0}, {Q: 0, R: 1}, {Q: 1, R: 0}, {Q: 1, R: 1}}, hexes)
+
+	DistanceFrom(Hex{Q: 1, R: 1}).Sort(hexes)
+	assert.Equal(t, []Hex{{Q: 1, R: 1}, {Q: 1, R: 0}, {Q: 0, R: 1}, {Q: 0, R: 0}}, hexes)
+}
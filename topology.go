@@ -0,0 +1,149 @@
+package hex
+
+import (
+	"fmt"
+	"math"
+)
+
+// Topology describes how the edges of a hex world connect.
+//
+// In a wrapped world, many hexes refer to the same place.
+// Canonical picks one of them, so hexes from a Topology
+// can be compared with == and used as map keys.
+type Topology interface {
+	// Canonical returns the hex that h wraps to.
+	Canonical(h Hex) Hex
+	// DistanceTo returns the number of steps between a and b,
+	// taking the shortest way around the world.
+	DistanceTo(a, b Hex) int64
+	// Neighbor returns the canonical neighbor of h in the given direction.
+	Neighbor(h Hex, direction int) Hex
+}
+
+var (
+	_ Topology = Planar{}
+	_ Topology = Cylinder{}
+	_ Topology = HexTorus{}
+)
+
+// Planar is an infinite world with no wrapping.
+type Planar struct{}
+
+// Canonical returns h.
+func (p Planar) Canonical(h Hex) Hex {
+	return h
+}
+
+// DistanceTo returns a.DistanceTo(b).
+func (p Planar) DistanceTo(a, b Hex) int64 {
+	return a.DistanceTo(b)
+}
+
+// Neighbor returns h.Neighbor(direction).
+func (p Planar) Neighbor(h Hex, direction int) Hex {
+	return h.Neighbor(direction)
+}
+
+// Cylinder is a world that wraps east-west.
+//
+// Width is the number of hexes in each row, and must be positive.
+// The methods panic if it isn't, so the zero Cylinder can't be used.
+// Canonical hexes have an OddR offset column in [0, Width).
+// Moving Width hexes in direction 0 gets you back where you started.
+type Cylinder struct {
+	Width int64
+}
+
+// floorDiv divides, rounding toward negative infinity.
+func floorDiv(a, b int64) int64 {
+	d := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		d--
+	}
+	return d
+}
+
+// Canonical returns the hex that h wraps to.
+func (c Cylinder) Canonical(h Hex) Hex {
+	if c.Width <= 0 {
+		panic(fmt.Sprintf("cylinder width must be positive, got %d", c.Width))
+	}
+	col := h.ToOffset(OddR).Col
+	return Hex{Q: h.Q - floorDiv(col, c.Width)*c.Width, R: h.R}
+}
+
+// DistanceTo returns the number of steps between a and b,
+// taking the shortest way around the cylinder.
+func (c Cylinder) DistanceTo(a, b Hex) int64 {
+	a, b = c.Canonical(a), c.Canonical(b)
+	best := a.DistanceTo(b)
+	for _, k := range []int64{-1, 1} {
+		best = minInt(best, a.DistanceTo(Hex{Q: b.Q + k*c.Width, R: b.R}))
+	}
+	return best
+}
+
+// Neighbor returns the canonical neighbor of h in the given direction.
+func (c Cylinder) Neighbor(h Hex, direction int) Hex {
+	return c.Canonical(h.Neighbor(direction))
+}
+
+// HexTorus is a hexagon-shaped world that wraps in all
+// six directions.
+//
+// Radius is the number of rings around the origin, and must not be negative.
+// The methods panic if it is.
+// Canonical hexes are the ones in Spiral(Origin(), Radius).
+// Walking off one edge brings you back in on the opposite edge.
+//
+// This works by tiling the plane with copies of the world,
+// centered on the mirror centers.
+type HexTorus struct {
+	Radius int64
+}
+
+// mirrorCenter returns the center of one of the six
+// copies of the world that border the original.
+func (t HexTorus) mirrorCenter(direction int) Hex {
+	return Hex{Q: 2*t.Radius + 1, R: -t.Radius}.Rotate(Origin(), direction)
+}
+
+// Canonical returns the hex that h wraps to.
+func (t HexTorus) Canonical(h Hex) Hex {
+	n := t.Radius
+	if n < 0 {
+		panic(fmt.Sprintf("torus radius must not be negative, got %d", n))
+	}
+
+	// jump most of the way there by solving for
+	// the number of copies in each of two directions.
+	if h.Length() > 2*n+1 {
+		size := float64(3*n*n + 3*n + 1)
+		a := int64(math.Round(float64(h.Q*(n+1)-h.R*n) / size))
+		b := int64(math.Round(float64(h.R*(2*n+1)+h.Q*n) / size))
+		h = h.Subtract(Hex{Q: 2*n + 1, R: -n}.Multiply(a)).Subtract(Hex{Q: n, R: n + 1}.Multiply(b))
+	}
+
+	// then step toward the nearest mirror center until we're inside.
+	for h.Length() > n {
+		nearest := t.mirrorCenter(0)
+		for i := 1; i < 6; i++ {
+			if m := t.mirrorCenter(i); h.DistanceTo(m) < h.DistanceTo(nearest) {
+				nearest = m
+			}
+		}
+		h = h.Subtract(nearest)
+	}
+	return h
+}
+
+// DistanceTo returns the number of steps between a and b,
+// taking the shortest way around the torus.
+func (t HexTorus) DistanceTo(a, b Hex) int64 {
+	return t.Canonical(b.Subtract(a)).Length()
+}
+
+// Neighbor returns the canonical neighbor of h in the given direction.
+func (t HexTorus) Neighbor(h Hex, direction int) Hex {
+	return t.Canonical(h.Neighbor(direction))
+}
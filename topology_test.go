@@ -0,0 +1,110 @@
+package hex
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTopologyHexes() []Hex {
+	rng := rand.New(rand.NewSource(15))
+	hexes := Spiral(Origin(), 3)
+	for i := 0; i < 200; i++ {
+		hexes = append(hexes, Hex{Q: rng.Int63n(201) - 100, R: rng.Int63n(201) - 100})
+	}
+	return hexes
+}
+
+func TestPlanar(t *testing.T) {
+	for _, h := range testTopologyHexes() {
+		assert.Equal(t, h, Planar{}.Canonical(h))
+		assert.Equal(t, h.DistanceTo(Origin()), Planar{}.DistanceTo(h, Origin()))
+		assert.Equal(t, h.Neighbor(2), Planar{}.Neighbor(h, 2))
+	}
+}
+
+func TestCylinder(t *testing.T) {
+	for _, width := range []int64{1, 2, 7, 10} {
+		c := Cylinder{Width: width}
+		for _, h := range testTopologyHexes() {
+			canon := c.Canonical(h)
+			col := canon.ToOffset(OddR).Col
+			require.True(t, col >= 0 && col < width, "%s wrapped to %s", h, canon)
+			require.Equal(t, h.R, canon.R)
+			require.Equal(t, canon, c.Canonical(canon))
+			require.Equal(t, canon, c.Canonical(h.Add(Hex{Q: 3 * width})))
+
+			// distance is the best of all the copies.
+			best := h.DistanceTo(Origin())
+			for k := int64(-300); k <= 300; k++ {
+				best = minInt(best, h.Add(Hex{Q: k * width}).DistanceTo(Origin()))
+			}
+			require.Equal(t, best, c.DistanceTo(h, Origin()), "%s in width %d", h, width)
+			require.Equal(t, best, c.DistanceTo(Origin(), h), "%s in width %d", h, width)
+		}
+	}
+
+	// walking east wraps around.
+	c := Cylinder{Width: 5}
+	h := Origin()
+	for i := 0; i < 5; i++ {
+		h = c.Neighbor(h, 0)
+	}
+	assert.Equal(t, Origin(), h)
+	assert.EqualValues(t, 1, c.DistanceTo(Hex{Q: 4, R: 0}, Origin()))
+}
+
+func TestBadTopology(t *testing.T) {
+	for _, width := range []int64{0, -3} {
+		c := Cylinder{Width: width}
+		assert.PanicsWithValue(t, fmt.Sprintf("cylinder width must be positive, got %d", width), func() { c.Canonical(Origin()) })
+		assert.Panics(t, func() { c.DistanceTo(Origin(), Hex{Q: 1}) })
+		assert.Panics(t, func() { c.Neighbor(Origin(), 0) })
+	}
+	assert.PanicsWithValue(t, "torus radius must not be negative, got -1", func() { HexTorus{Radius: -1}.Canonical(Origin()) })
+}
+
+func TestHexTorus(t *testing.T) {
+	for _, radius := range []int64{0, 1, 2, 5} {
+		torus := HexTorus{Radius: radius}
+		size := 3*radius*radius + 3*radius + 1
+
+		seen := make(map[Hex]bool)
+		for _, h := range testTopologyHexes() {
+			canon := torus.Canonical(h)
+			require.LessOrEqual(t, canon.Length(), radius, "%s wrapped to %s", h, canon)
+			require.Equal(t, canon, torus.Canonical(canon))
+			for i := 0; i < 6; i++ {
+				require.Equal(t, canon, torus.Canonical(h.Add(torus.mirrorCenter(i))))
+				require.Equal(t, canon, torus.Canonical(h.Add(torus.mirrorCenter(i).Multiply(7))))
+			}
+			seen[canon] = true
+		}
+		require.LessOrEqual(t, int64(len(seen)), size)
+
+		// every hex in the world has neighbors in the world,
+		// and they are all different.
+		for _, h := range Spiral(Origin(), radius) {
+			neighbors := make(map[Hex]bool)
+			for i := 0; i < 6; i++ {
+				n := torus.Neighbor(h, i)
+				require.LessOrEqual(t, n.Length(), radius)
+				if size > 6 {
+					require.EqualValues(t, 1, torus.DistanceTo(h, n))
+				}
+				neighbors[n] = true
+			}
+			if size > 7 {
+				require.Len(t, neighbors, 6)
+			}
+		}
+	}
+
+	// walking off an edge comes back in on the opposite one.
+	torus := HexTorus{Radius: 3}
+	assert.Equal(t, Hex{Q: -3, R: 0}, torus.Neighbor(Hex{Q: 3, R: -3}, 0))
+	assert.EqualValues(t, 1, torus.DistanceTo(Hex{Q: 3, R: -3}, Hex{Q: -3, R: 0}))
+}
@@ -0,0 +1,130 @@
+package hex
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/erinpentecost/hex/internal"
+)
+
+// Transform is an affine transformation on hexes.
+//
+// It is a matrix that multiplies the column vector (q, r, s, 1):
+//
+// [[qq, qr, qs, tq]
+//
+// [rq, rr, rs, tr]
+//
+// [sq, sr, ss, ts]
+//
+// [0, 0, 0, 1]]
+//
+// Build transforms with the constructors below and combine them
+// with Compose, rather than writing out matrices by hand.
+type Transform [4][4]int64
+
+// ErrBadTransform is returned when a transform would move
+// hexes off the q+r+s=0 plane.
+var ErrBadTransform = errors.New("transform does not preserve q+r+s=0")
+
+// ErrTransformNotInvertible is returned when a transform
+// has no inverse on the hex grid.
+var ErrTransformNotInvertible = errors.New("transform is not invertible")
+
+// IdentityTransform returns a transform that does nothing.
+func IdentityTransform() Transform {
+	return internal.RotationMatrixes[0]
+}
+
+// TranslateTransform returns a transform that adds offset to hexes.
+func TranslateTransform(offset Hex) Transform {
+	return internal.TranslateMatrix(offset.Q, offset.R, offset.S())
+}
+
+// RotateTransform returns a transform that rotates hexes about pivot,
+// the same way as Hex.Rotate.
+func RotateTransform(pivot Hex, direction int) Transform {
+	return TranslateTransform(pivot.Multiply(-1)).
+		Compose(internal.RotateMatrix(direction)).
+		Compose(TranslateTransform(pivot))
+}
+
+// ReflectTransform returns a transform that mirrors hexes across
+// the axis that passes through pivot.
+func ReflectTransform(pivot Hex, axis Axis) Transform {
+	return TranslateTransform(pivot.Multiply(-1)).
+		Compose(internal.ReflectMatrix(int(axis))).
+		Compose(TranslateTransform(pivot))
+}
+
+// NewTransform checks that a matrix is a valid transform.
+func NewTransform(m [4][4]int64) (Transform, error) {
+	t := Transform(m)
+	return t, t.Validate()
+}
+
+// Validate returns ErrBadTransform if t would move hexes
+// off the q+r+s=0 plane.
+func (t Transform) Validate() error {
+	if t[3] != [4]int64{0, 0, 0, 1} {
+		return fmt.Errorf("%w: last row must be [0 0 0 1]", ErrBadTransform)
+	}
+	// the output sums to zero for every input on the plane
+	// only if every column sums to the same thing
+	// and the translation sums to zero.
+	var sums [4]int64
+	for j := 0; j < 4; j++ {
+		sums[j] = t[0][j] + t[1][j] + t[2][j]
+	}
+	if sums[0] != sums[1] || sums[1] != sums[2] || sums[3] != 0 {
+		return fmt.Errorf("%w: %v", ErrBadTransform, [4][4]int64(t))
+	}
+	return nil
+}
+
+// Compose returns a transform that applies t, then next.
+func (t Transform) Compose(next Transform) Transform {
+	return internal.MatrixMultiply(next, t)
+}
+
+// Apply transforms h.
+func (t Transform) Apply(h Hex) Hex {
+	// no need to transform S since it's a derived field.
+	return Hex{
+		Q: t[0][0]*h.Q + t[0][1]*h.R + t[0][2]*h.S() + t[0][3],
+		R: t[1][0]*h.Q + t[1][1]*h.R + t[1][2]*h.S() + t[1][3],
+	}
+}
+
+// Inverse returns the transform that undoes t.
+//
+// Only transforms that don't scale have inverses on the hex grid.
+// Every transform made with the constructors in this package does.
+func (t Transform) Inverse() (Transform, error) {
+	if err := t.Validate(); err != nil {
+		return Transform{}, err
+	}
+
+	// s is derived, so fold it into q and r to get
+	// a 2x2 matrix and a translation.
+	a := t[0][0] - t[0][2]
+	b := t[0][1] - t[0][2]
+	c := t[1][0] - t[1][2]
+	d := t[1][1] - t[1][2]
+	det := a*d - b*c
+	if det != 1 && det != -1 {
+		return Transform{}, ErrTransformNotInvertible
+	}
+
+	// the inverse of a 2x2 matrix with a determinant of ±1.
+	ia, ib, ic, id := d*det, -b*det, -c*det, a*det
+	tq := -(ia*t[0][3] + ib*t[1][3])
+	tr := -(ic*t[0][3] + id*t[1][3])
+
+	return Transform{
+		{ia, ib, 0, tq},
+		{ic, id, 0, tr},
+		{-ia - ic, -ib - id, 0, -tq - tr},
+		{0, 0, 0, 1},
+	}, nil
+}
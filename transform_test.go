@@ -0,0 +1,84 @@
+package hex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTransforms() map[string]Transform {
+	pivot := Hex{Q: 2, R: -3}
+	transforms := map[string]Transform{
+		"identity":  IdentityTransform(),
+		"translate": TranslateTransform(Hex{Q: -4, R: 9}),
+		"rotate-0":  RotateTransform(pivot, 0),
+		"rotate-1":  RotateTransform(pivot, 1),
+		"rotate-4":  RotateTransform(pivot, 4),
+		"reflect-q": ReflectTransform(pivot, AxisQ),
+		"reflect-s": ReflectTransform(pivot, AxisSQ),
+	}
+	transforms["composed"] = transforms["rotate-1"].
+		Compose(transforms["translate"]).
+		Compose(transforms["reflect-s"])
+	return transforms
+}
+
+func TestTransformConstructors(t *testing.T) {
+	pivot := Hex{Q: 2, R: -3}
+	for _, h := range Spiral(Hex{Q: 1, R: 1}, 3) {
+		assert.Equal(t, h, IdentityTransform().Apply(h))
+		assert.Equal(t, h.Add(Hex{Q: -4, R: 9}), TranslateTransform(Hex{Q: -4, R: 9}).Apply(h))
+		for d := 0; d < 6; d++ {
+			assert.Equal(t, h.Rotate(pivot, d), RotateTransform(pivot, d).Apply(h))
+		}
+		for _, axis := range []Axis{AxisQ, AxisR, AxisS, AxisQR, AxisRS, AxisSQ} {
+			assert.Equal(t, h.Subtract(pivot).Reflect(axis).Add(pivot), ReflectTransform(pivot, axis).Apply(h))
+		}
+	}
+}
+
+func TestTransformComposeAndInverse(t *testing.T) {
+	transforms := testTransforms()
+	for name, tr := range transforms {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, tr.Validate())
+
+			inv, err := tr.Inverse()
+			require.NoError(t, err)
+			require.NoError(t, inv.Validate())
+
+			for _, h := range Spiral(Hex{Q: -5, R: 2}, 3) {
+				assert.Equal(t, h, inv.Apply(tr.Apply(h)))
+				assert.Equal(t, h, tr.Apply(inv.Apply(h)))
+				assert.Equal(t, h, tr.Compose(inv).Apply(h))
+
+				// compose applies the receiver first.
+				other := transforms["translate"]
+				assert.Equal(t, other.Apply(tr.Apply(h)), tr.Compose(other).Apply(h))
+			}
+		})
+	}
+}
+
+func TestTransformValidate(t *testing.T) {
+	_, err := NewTransform([4][4]int64{{1, 0, 0, 1}, {0, 1, 0, 0}, {0, 0, 1, 0}, {0, 0, 0, 1}})
+	assert.ErrorIs(t, err, ErrBadTransform)
+
+	_, err = NewTransform([4][4]int64{{2, 0, 0, 0}, {0, 1, 0, 0}, {0, 0, 1, 0}, {0, 0, 0, 1}})
+	assert.ErrorIs(t, err, ErrBadTransform)
+
+	_, err = NewTransform([4][4]int64{{1, 0, 0, 0}, {0, 1, 0, 0}, {0, 0, 1, 0}, {1, 0, 0, 1}})
+	assert.ErrorIs(t, err, ErrBadTransform)
+
+	// scaling is valid, but can't be undone.
+	scale, err := NewTransform([4][4]int64{{2, 0, 0, 0}, {0, 2, 0, 0}, {0, 0, 2, 0}, {0, 0, 0, 1}})
+	require.NoError(t, err)
+	assert.Equal(t, Hex{Q: 2, R: -4}, scale.Apply(Hex{Q: 1, R: -2}))
+	_, err = scale.Inverse()
+	assert.ErrorIs(t, err, ErrTransformNotInvertible)
+
+	tr, err := NewTransform(TranslateTransform(Hex{Q: 1, R: 2}))
+	require.NoError(t, err)
+	assert.Equal(t, Hex{Q: 1, R: 2}, tr.Apply(Origin()))
+}